@@ -0,0 +1,156 @@
+package radio
+
+// PowerController is implemented by radios with adjustable transmit power.
+type PowerController interface {
+	TransmitPower() int
+	SetTransmitPower(dBm int)
+}
+
+// DataRater is implemented by radios with an adjustable data rate.
+type DataRater interface {
+	DataRate() uint32
+	SetDataRate(bitsPerSecond uint32)
+}
+
+// AdaptiveController steps a radio's transmit power and data rate
+// according to the delivery outcomes reported to it.
+// While links have spare margin, it first lowers power and then raises
+// the data rate; on repeated losses it undoes those steps in reverse.
+// Either adjustment is skipped if no radio in the wrapped chain supports it.
+type AdaptiveController struct {
+	radio Interface
+
+	// MinPower and MaxPower bound the transmit power, in dBm.
+	MinPower int
+	MaxPower int
+	// PowerStep is the power adjustment in dB.
+	PowerStep int
+	// Rates lists the usable data rates in increasing order.
+	Rates []uint32
+	// Margin is the link margin in dB above which the link is
+	// considered to have room to spare.
+	Margin float64
+	// Successes is the number of consecutive successes with spare margin
+	// required before stepping down; Failures is the number of consecutive
+	// failures required before stepping back up.
+	Successes int
+	Failures  int
+
+	good int
+	bad  int
+}
+
+// NewAdaptiveController returns a controller for r with the given
+// power range and data rates, using a 10 dB margin and requiring
+// 10 successes or 2 failures before each adjustment.
+func NewAdaptiveController(r Interface, minPower, maxPower int, rates []uint32) *AdaptiveController {
+	return &AdaptiveController{
+		radio:     r,
+		MinPower:  minPower,
+		MaxPower:  maxPower,
+		PowerStep: 2,
+		Rates:     rates,
+		Margin:    10,
+		Successes: 10,
+		Failures:  2,
+	}
+}
+
+// Success reports a delivered packet whose link margin was the given number of dB.
+func (c *AdaptiveController) Success(margin float64) {
+	c.bad = 0
+	if margin < c.Margin {
+		c.good = 0
+		return
+	}
+	c.good++
+	if c.good < c.Successes {
+		return
+	}
+	c.good = 0
+	if !c.lowerPower() {
+		c.raiseRate()
+	}
+}
+
+// Failure reports a lost packet.
+func (c *AdaptiveController) Failure() {
+	c.good = 0
+	c.bad++
+	if c.bad < c.Failures {
+		return
+	}
+	c.bad = 0
+	if !c.lowerRate() {
+		c.raisePower()
+	}
+}
+
+// power returns the PowerController in the radio's wrapped chain.
+func (c *AdaptiveController) power() (PowerController, bool) {
+	p, ok := Find(c.radio, isPowerController).(PowerController)
+	return p, ok
+}
+
+// rate returns the DataRater in the radio's wrapped chain.
+func (c *AdaptiveController) rate() (DataRater, bool) {
+	d, ok := Find(c.radio, isDataRater).(DataRater)
+	return d, ok
+}
+
+func (c *AdaptiveController) lowerPower() bool {
+	p, ok := c.power()
+	if !ok || p.TransmitPower()-c.PowerStep < c.MinPower {
+		return false
+	}
+	p.SetTransmitPower(p.TransmitPower() - c.PowerStep)
+	return true
+}
+
+func (c *AdaptiveController) raisePower() bool {
+	p, ok := c.power()
+	if !ok || p.TransmitPower() >= c.MaxPower {
+		return false
+	}
+	power := p.TransmitPower() + c.PowerStep
+	if power > c.MaxPower {
+		power = c.MaxPower
+	}
+	p.SetTransmitPower(power)
+	return true
+}
+
+func (c *AdaptiveController) raiseRate() bool {
+	d, ok := c.rate()
+	if !ok {
+		return false
+	}
+	i := c.rateIndex(d.DataRate())
+	if i < 0 || i+1 >= len(c.Rates) {
+		return false
+	}
+	d.SetDataRate(c.Rates[i+1])
+	return true
+}
+
+func (c *AdaptiveController) lowerRate() bool {
+	d, ok := c.rate()
+	if !ok {
+		return false
+	}
+	i := c.rateIndex(d.DataRate())
+	if i <= 0 {
+		return false
+	}
+	d.SetDataRate(c.Rates[i-1])
+	return true
+}
+
+func (c *AdaptiveController) rateIndex(rate uint32) int {
+	for i, r := range c.Rates {
+		if r == rate {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,63 @@
+package radio_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+// powerRadio is a simulated radio with an adjustable transmit power and data rate.
+type powerRadio struct {
+	rateRadio
+	power int
+}
+
+func (r *powerRadio) TransmitPower() int { return r.power }
+
+func (r *powerRadio) SetTransmitPower(dBm int) { r.power = dBm }
+
+func (r *powerRadio) Unwrap() radio.Interface { return r.Radio }
+
+func TestAdaptiveController(t *testing.T) {
+	rates := []uint32{1200, 4800, 9600}
+	const (
+		good = iota
+		lost
+	)
+	cases := []struct {
+		name   string
+		events []int
+		count  int
+		power  int
+		rate   uint32
+	}{
+		{"steady", nil, 0, 10, 4800},
+		{"lower power", []int{good}, 10, 8, 4800},
+		{"power floor then rate", []int{good}, 60, 0, 9600},
+		{"lower rate", []int{lost}, 2, 10, 1200},
+		{"raise power", []int{good, lost}, 10, 10, 1200},
+		{"one loss", []int{lost}, 1, 10, 4800},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			inner := &powerRadio{rateRadio: rateRadio{Radio: a, rate: 4800}, power: 10}
+			// The controller must find the settings through wrappers that lack them.
+			r := radio.NewPacketGap(radio.NewTraced(inner, nil), time.Millisecond)
+			ctl := radio.NewAdaptiveController(r, 0, 10, rates)
+			for _, e := range c.events {
+				for i := 0; i < c.count; i++ {
+					if e == good {
+						ctl.Success(20)
+					} else {
+						ctl.Failure()
+					}
+				}
+			}
+			if inner.power != c.power || inner.rate != c.rate {
+				t.Errorf("power %d rate %d, want power %d rate %d", inner.power, inner.rate, c.power, c.rate)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package radio
+
+import (
+	"time"
+)
+
+// FrequencyErrorReader is implemented by radios that can estimate
+// the frequency error of the most recently received packet, in Hertz,
+// as the remote transmitter's frequency minus the radio's own.
+type FrequencyErrorReader interface {
+	FrequencyError() int
+}
+
+// AFC wraps a radio to track the frequency offset of a remote
+// transmitter. After each packet it reads the radio's frequency
+// error estimate and updates an exponentially weighted average.
+// When Apply is set, the radio is tuned to the nominal frequency
+// plus that offset, while Frequency continues to report the nominal one.
+// Tracking requires a FrequencyErrorReader in the wrapped chain.
+type AFC struct {
+	Interface
+	// Alpha is the weight given to each new estimate (0 < Alpha <= 1).
+	// Values outside that range are treated as 1.
+	Alpha float64
+	// Apply retunes the radio to compensate for the tracked offset.
+	Apply bool
+	// MaxOffset, if positive, bounds the magnitude of the tracked offset
+	// in Hertz, so that spurious estimates cannot retune the radio
+	// far from the nominal frequency.
+	MaxOffset int
+
+	nominal uint32
+	offset  float64
+	samples int
+}
+
+// NewAFC returns an AFC wrapper for r with the given smoothing factor.
+func NewAFC(r Interface, alpha float64, apply bool) *AFC {
+	return &AFC{Interface: r, Alpha: alpha, Apply: apply, nominal: r.Frequency()}
+}
+
+// Unwrap returns the radio wrapped by a.
+func (a *AFC) Unwrap() Interface {
+	return a.Interface
+}
+
+// Offset returns the tracked frequency offset in Hertz.
+func (a *AFC) Offset() int {
+	return int(a.offset)
+}
+
+// SetOffset sets the tracked frequency offset, for example from a saved value.
+func (a *AFC) SetOffset(hz int) {
+	a.offset = float64(hz)
+	a.samples = 1
+	a.retune()
+}
+
+// Init initializes the radio at the given nominal frequency.
+func (a *AFC) Init(frequency uint32) {
+	a.nominal = frequency
+	a.Interface.Init(a.tuned())
+}
+
+// Frequency returns the nominal frequency.
+func (a *AFC) Frequency() uint32 {
+	return a.nominal
+}
+
+// SetFrequency sets the nominal frequency, applying the tracked offset if enabled.
+func (a *AFC) SetFrequency(freq uint32) {
+	a.nominal = freq
+	a.Interface.SetFrequency(a.tuned())
+}
+
+func (a *AFC) tuned() uint32 {
+	if !a.Apply {
+		return a.nominal
+	}
+	return uint32(int64(a.nominal) + int64(a.offset))
+}
+
+func (a *AFC) retune() {
+	if a.Apply && a.Interface.Frequency() != a.tuned() {
+		a.Interface.SetFrequency(a.tuned())
+	}
+}
+
+// Receive receives a packet and updates the frequency offset estimate.
+func (a *AFC) Receive(timeout time.Duration) ([]byte, int) {
+	data, rssi := a.Interface.Receive(timeout)
+	a.update(data)
+	return data, rssi
+}
+
+// SendAndReceive sends data, receives a reply, and updates the frequency offset estimate.
+func (a *AFC) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	reply, rssi := a.Interface.SendAndReceive(data, timeout)
+	a.update(reply)
+	return reply, rssi
+}
+
+func isFrequencyErrorReader(r Interface) bool {
+	_, ok := r.(FrequencyErrorReader)
+	return ok
+}
+
+func (a *AFC) update(data []byte) {
+	if data == nil || a.Error() != nil {
+		return
+	}
+	f, ok := Find(a.Interface, isFrequencyErrorReader).(FrequencyErrorReader)
+	if !ok {
+		return
+	}
+	// The estimate is relative to the frequency the radio is actually tuned to.
+	measured := float64(int64(a.Interface.Frequency())-int64(a.nominal)) + float64(f.FrequencyError())
+	alpha := a.Alpha
+	if alpha <= 0 || alpha > 1 || a.samples == 0 {
+		alpha = 1
+	}
+	a.offset += alpha * (measured - a.offset)
+	if max := float64(a.MaxOffset); max > 0 {
+		if a.offset > max {
+			a.offset = max
+		} else if a.offset < -max {
+			a.offset = -max
+		}
+	}
+	a.samples++
+	a.retune()
+}
@@ -0,0 +1,81 @@
+package radio_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+// afcRadio receives a packet on every call and reports the
+// frequency error of a transmitter at a fixed frequency.
+type afcRadio struct {
+	*sim.Radio
+	remote uint32
+}
+
+func (r *afcRadio) Receive(time.Duration) ([]byte, int) { return []byte{1}, -50 }
+
+func (r *afcRadio) FrequencyError() int { return int(int64(r.remote) - int64(r.Frequency())) }
+
+func (r *afcRadio) Unwrap() radio.Interface { return r.Radio }
+
+func TestAFC(t *testing.T) {
+	const nominal = 916500000
+	cases := []struct {
+		name      string
+		alpha     float64
+		apply     bool
+		maxOffset int
+		remote    uint32
+		offsets   []int
+	}{
+		{"first estimate", 0.5, false, 0, nominal + 800, []int{800}},
+		{"smoothed", 0.5, false, 0, nominal + 800, []int{800, 800, 800}},
+		{"applied", 0.5, true, 0, nominal - 1000, []int{-1000, -1000}},
+		{"clamped", 0.5, true, 500, nominal + 2000, []int{500, 500}},
+		{"clamped negative", 1, false, 500, nominal - 2000, []int{-500}},
+		{"alpha out of range", 0, false, 0, nominal + 300, []int{300, 300}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			r := &afcRadio{Radio: a, remote: c.remote}
+			afc := radio.NewAFC(r, c.alpha, c.apply)
+			afc.MaxOffset = c.maxOffset
+			for i, want := range c.offsets {
+				afc.Receive(time.Millisecond)
+				if got := afc.Offset(); got != want {
+					t.Errorf("receive %d: offset %d, want %d", i, got, want)
+				}
+				tuned := uint32(nominal)
+				if c.apply {
+					tuned = uint32(int64(nominal) + int64(want))
+				}
+				if f := a.Frequency(); f != tuned {
+					t.Errorf("receive %d: tuned to %d, want %d", i, f, tuned)
+				}
+				if f := afc.Frequency(); f != nominal {
+					t.Errorf("receive %d: Frequency() = %d, want %d", i, f, nominal)
+				}
+			}
+		})
+	}
+}
+
+func TestAFCSmoothing(t *testing.T) {
+	const nominal = 916500000
+	_, a, _ := simPair(t)
+	r := &afcRadio{Radio: a, remote: nominal + 1000}
+	afc := radio.NewAFC(r, 0.25, false)
+	afc.Receive(time.Millisecond)
+	r.remote = nominal + 2000
+	want := []int{1250, 1437, 1578}
+	for i, w := range want {
+		afc.Receive(time.Millisecond)
+		if got := afc.Offset(); got != w {
+			t.Errorf("step %d: offset %d, want %d", i, got, w)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package radio
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+)
+
+// AlignedBuffers returns an Option that allocates SPI transfer buffers
+// starting on a multiple of align bytes and, when they fit, within a
+// single memory page. Some spidev DMA implementations need this to
+// avoid copying large bursts through bounce buffers.
+// The alignment must be a power of two; otherwise Open fails.
+func AlignedBuffers(align int) Option {
+	return func(h *Hardware) {
+		if align <= 0 || align&(align-1) != 0 {
+			h.err = fmt.Errorf("invalid buffer alignment (%d)", align)
+			return
+		}
+		h.alignment = align
+	}
+}
+
+// newBuffer allocates a transfer buffer of n bytes.
+func (h *Hardware) newBuffer(n int) []byte {
+	if h.alignment <= 1 {
+		return make([]byte, n)
+	}
+	return alignedBuffer(n, h.alignment, os.Getpagesize())
+}
+
+// buffer returns a transfer buffer of n bytes for a burst. Aligned
+// buffers are reused from one burst to the next and only reallocated
+// to grow, so callers must copy out any data they return.
+func (h *Hardware) buffer(n int) []byte {
+	if h.alignment <= 1 {
+		return make([]byte, n)
+	}
+	if cap(h.burst) < n {
+		h.burst = h.newBuffer(n)
+	}
+	return h.burst[:n]
+}
+
+// alignedBuffer returns a slice of n bytes starting at a multiple of align
+// that does not cross a page boundary unless n is larger than a page,
+// in which case it starts on a page boundary so that any prefix of up to
+// a page still fits within one.
+// It relies on the Go heap not moving allocated objects.
+func alignedBuffer(n int, align int, page int) []byte {
+	if n > page && align < page {
+		align = page
+	}
+	raw := make([]byte, n+page+align)
+	base := uintptr(unsafe.Pointer(&raw[0]))
+	mask := uintptr(align - 1)
+	off := int((align - int(base&mask)) & (align - 1))
+	if n <= page {
+		for int((base+uintptr(off))%uintptr(page))+n > page {
+			off += align
+		}
+	}
+	return raw[off : off+n : off+n]
+}
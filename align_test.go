@@ -0,0 +1,89 @@
+package radio
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestAlignedBuffer(t *testing.T) {
+	cases := []struct {
+		n, align, page int
+	}{
+		{2, 8, 4096},
+		{64, 64, 4096},
+		{4000, 64, 4096},
+		{4096, 4096, 4096},
+		{10000, 64, 4096},
+		{100, 256, 128},
+	}
+	for _, c := range cases {
+		buf := alignedBuffer(c.n, c.align, c.page)
+		if len(buf) != c.n || cap(buf) != c.n {
+			t.Errorf("alignedBuffer(%d, %d, %d) has length %d and capacity %d", c.n, c.align, c.page, len(buf), cap(buf))
+			continue
+		}
+		base := int(uintptr(unsafe.Pointer(&buf[0])))
+		if base%c.align != 0 {
+			t.Errorf("alignedBuffer(%d, %d, %d) starts at %#x", c.n, c.align, c.page, base)
+		}
+		if c.n <= c.page && base%c.page+c.n > c.page {
+			t.Errorf("alignedBuffer(%d, %d, %d) at %#x crosses a page", c.n, c.align, c.page, base)
+		}
+		if c.n > c.page && base%c.page != 0 {
+			t.Errorf("alignedBuffer(%d, %d, %d) at %#x does not start on a page", c.n, c.align, c.page, base)
+		}
+	}
+}
+
+func TestAlignedBuffersOption(t *testing.T) {
+	cases := []struct {
+		align int
+		ok    bool
+	}{
+		{1, true},
+		{2, true},
+		{64, true},
+		{0, false},
+		{-8, false},
+		{3, false},
+		{48, false},
+	}
+	for _, c := range cases {
+		h := openTest(AlignedBuffers(c.align))
+		if err := h.Error(); (err == nil) != c.ok {
+			t.Errorf("AlignedBuffers(%d): error = %v", c.align, err)
+		}
+		h.Close()
+	}
+}
+
+func TestAlignedBufferReuse(t *testing.T) {
+	h := openTest(AlignedBuffers(64))
+	defer h.Close()
+	cases := []struct {
+		name  string
+		n     int
+		grows bool
+	}{
+		{"first", 32, true},
+		{"smaller", 16, false},
+		{"same", 32, false},
+		{"larger", 128, true},
+		{"smaller again", 64, false},
+	}
+	for _, c := range cases {
+		prev := h.burst
+		h.WriteBurst(0x00, make([]byte, c.n))
+		data := h.ReadBurst(0x00, c.n)
+		if err := h.Error(); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		grew := cap(prev) == 0 || &prev[:1][0] != &h.burst[:1][0]
+		if grew != c.grows {
+			t.Errorf("%s: buffer reallocated = %v, want %v", c.name, grew, c.grows)
+		}
+		if &data[0] == &h.burst[1] {
+			t.Errorf("%s: ReadBurst returned the reused buffer", c.name)
+		}
+	}
+}
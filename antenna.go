@@ -0,0 +1,109 @@
+package radio
+
+// AntennaFlavor is implemented by flavors for modules with external
+// RF switches, power amplifiers, or LNAs controlled by GPIO lines.
+// The transmit line is driven high while transmitting and the receive
+// line while receiving; a negative pin number means the line is absent.
+// The lines are set up for receive when the device is opened.
+type AntennaFlavor interface {
+	AntennaPins() (tx int, rx int)
+}
+
+// TransmitStartFlavor is implemented by flavors that identify the
+// register write which starts a transmission, such as a TX command
+// strobe or an operating mode change. The RF path is then switched to
+// transmit automatically just before that write, and back to receive
+// by AwaitTransmit, so drivers need not call AntennaTransmit.
+type TransmitStartFlavor interface {
+	StartsTransmit(addr byte, value byte) bool
+}
+
+type antennaPins struct {
+	tx outputPin
+	rx outputPin
+}
+
+// off drives both lines low, ignoring errors.
+func (a *antennaPins) off() {
+	for _, p := range []outputPin{a.tx, a.rx} {
+		if p != nil {
+			_ = p.Write(false)
+		}
+	}
+}
+
+// openAntenna exports the antenna control lines and selects receive.
+func (h *Hardware) openAntenna() {
+	f, ok := h.flavor.(AntennaFlavor)
+	if !ok || h.isDryRun() {
+		return
+	}
+	tx, rx := f.AntennaPins()
+	a := &antennaPins{}
+	var err error
+	if tx >= 0 {
+		a.tx, err = openOutput(tx, false, false)
+	}
+	if err == nil && rx >= 0 {
+		a.rx, err = openOutput(rx, false, false)
+	}
+	if err != nil {
+		h.err = err
+		return
+	}
+	h.antenna = a
+	h.AntennaReceive()
+}
+
+// startsTransmit reports whether the flavor identifies the given
+// register write as the start of a transmission.
+func (h *Hardware) startsTransmit(addr byte, value byte) bool {
+	f, ok := h.flavor.(TransmitStartFlavor)
+	return ok && h.antenna != nil && f.StartsTransmit(addr, value)
+}
+
+func (h *Hardware) selectAntenna(transmit bool) {
+	a := h.antenna
+	if a == nil {
+		return
+	}
+	// Turn off the active path before turning on the other.
+	off, on := a.tx, a.rx
+	if transmit {
+		off, on = a.rx, a.tx
+	}
+	if off != nil {
+		h.err = off.Write(false)
+		if h.Error() != nil {
+			return
+		}
+	}
+	if on != nil {
+		h.err = on.Write(true)
+	}
+}
+
+// AntennaTransmit switches the RF path to transmit.
+// Drivers call it before starting a transmission, unless the flavor
+// implements TransmitStartFlavor, in which case it happens automatically.
+// It does nothing if the flavor does not implement AntennaFlavor.
+func (h *Hardware) AntennaTransmit() {
+	h.selectAntenna(true)
+}
+
+// restoreAntenna switches back to receive without losing an earlier error.
+func (h *Hardware) restoreAntenna() {
+	err := h.err
+	h.err = nil
+	h.AntennaReceive()
+	if err != nil {
+		h.err = err
+	}
+}
+
+// AntennaReceive switches the RF path to receive.
+// AwaitTransmit calls it automatically when a transmission completes.
+// It does nothing if the flavor does not implement AntennaFlavor.
+func (h *Hardware) AntennaReceive() {
+	h.selectAntenna(false)
+}
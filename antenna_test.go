@@ -0,0 +1,65 @@
+package radio
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakePin struct{ value bool }
+
+func (p *fakePin) Write(v bool) error {
+	p.value = v
+	return nil
+}
+
+// antennaFlavor switches to transmit when register 0x35 is written.
+type antennaFlavor struct{ testFlavor }
+
+// No pin numbers are declared, so Close does not unexport real GPIO lines.
+func (antennaFlavor) AntennaPins() (int, int) { return -1, -1 }
+
+func (antennaFlavor) StartsTransmit(addr byte, value byte) bool { return addr == 0x35 }
+
+func openAntennaTest(t *testing.T) (*Hardware, *fakePin, *fakePin) {
+	t.Helper()
+	h := Open(antennaFlavor{}, DryRun(nil))
+	if h.Error() != nil {
+		t.Fatal(h.Error())
+	}
+	// Dry-run devices do not touch GPIO, so install the pins directly.
+	tx, rx := &fakePin{}, &fakePin{}
+	h.antenna = &antennaPins{tx: tx, rx: rx}
+	h.AntennaReceive()
+	return h, tx, rx
+}
+
+func TestAntennaSwitching(t *testing.T) {
+	cases := []struct {
+		name   string
+		step   func(h *Hardware)
+		tx, rx bool
+	}{
+		{"Receive", func(h *Hardware) {}, false, true},
+		{"OtherWrite", func(h *Hardware) { h.WriteRegister(0x10, 1) }, false, true},
+		{"TransmitStart", func(h *Hardware) { h.WriteRegister(0x35, 0) }, true, false},
+		{"TransmitDone", func(h *Hardware) {
+			h.WriteRegister(0x35, 0)
+			h.AwaitTransmit()
+		}, false, true},
+		{"ErrorBeforeAwait", func(h *Hardware) {
+			h.WriteRegister(0x35, 0)
+			h.SetError(errors.New("failed"))
+			h.AwaitTransmit()
+		}, false, true},
+		{"Close", func(h *Hardware) { h.Close() }, false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, tx, rx := openAntennaTest(t)
+			c.step(h)
+			if tx.value != c.tx || rx.value != c.rx {
+				t.Errorf("tx %v rx %v, want tx %v rx %v", tx.value, rx.value, c.tx, c.rx)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package radio
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// BridgeOptions configures a Bridge.
+type BridgeOptions struct {
+	// AToB and BToA, if not nil, transform packets forwarded in each
+	// direction, for example to re-encode them. Returning nil drops the packet.
+	AToB func([]byte) []byte
+	BToA func([]byte) []byte
+	// AToBFrequency and BToAFrequency, if not zero, retune the receiving
+	// side to send packets forwarded in each direction on that frequency.
+	// The radio is returned to its own frequency after each packet.
+	AToBFrequency uint32
+	BToAFrequency uint32
+	// OneWay forwards packets from A to B only.
+	OneWay bool
+	// Listen is how long each side listens before the bridge
+	// turns to the other side.
+	Listen time.Duration
+	// Rate limits the number of packets per second forwarded in each
+	// direction; excess packets are dropped. Zero means no limit.
+	Rate float64
+	// Window is how long a forwarded packet is remembered, so that
+	// copies of it heard again on either side are not sent back.
+	Window time.Duration
+	// Clock provides the time. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// DefaultBridgeOptions are used for zero Listen and Window fields of BridgeOptions.
+var DefaultBridgeOptions = BridgeOptions{
+	Listen: 100 * time.Millisecond,
+	Window: 2 * time.Second,
+}
+
+// BridgeStats counts the packets handled by a Bridge.
+type BridgeStats struct {
+	AToB       int
+	BToA       int
+	Duplicates int
+	Limited    int
+}
+
+// Bridge forwards packets between two radios, such as a local sensor
+// link and a backhaul link on another band. A single goroutine listens
+// on each radio in turn, so neither is used concurrently.
+type Bridge struct {
+	a, b Interface
+	opts BridgeOptions
+	done chan struct{}
+	stop sync.Once
+	wg   sync.WaitGroup
+	clocked
+
+	mu    sync.Mutex
+	err   error
+	stats BridgeStats
+	seen  map[string]time.Time
+	aToB  tokenBucket
+	bToA  tokenBucket
+}
+
+// NewBridge starts forwarding packets between a and b.
+// The radios must not be used by other goroutines until the Bridge is stopped.
+func NewBridge(a, b Interface, opts BridgeOptions) *Bridge {
+	if opts.Listen <= 0 {
+		opts.Listen = DefaultBridgeOptions.Listen
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultBridgeOptions.Window
+	}
+	br := &Bridge{
+		a:       a,
+		b:       b,
+		opts:    opts,
+		clocked: clocked{opts.Clock},
+		done:    make(chan struct{}),
+		seen:    make(map[string]time.Time),
+		aToB:    tokenBucket{rate: opts.Rate},
+		bToA:    tokenBucket{rate: opts.Rate},
+	}
+	br.wg.Add(1)
+	go br.loop()
+	return br
+}
+
+// Stats returns the packet counts of the Bridge.
+func (br *Bridge) Stats() BridgeStats {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	return br.stats
+}
+
+// Err returns the most recent error from either radio other than a
+// receive timeout, or nil if there has been none. Such errors are
+// cleared and retried with an increasing delay, except that
+// a canceled wait, as after CancelWaits, stops the Bridge.
+func (br *Bridge) Err() error {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	return br.err
+}
+
+// Stop stops the Bridge after the current listening period.
+// It may be called more than once.
+func (br *Bridge) Stop() {
+	br.stop.Do(func() { close(br.done) })
+	br.wg.Wait()
+}
+
+func (br *Bridge) loop() {
+	defer br.wg.Done()
+	var backoff errorBackoff
+	for {
+		select {
+		case <-br.done:
+			return
+		default:
+		}
+		// Listen on both sides even if one is failing.
+		err := br.forward(br.a, br.b, br.opts.AToB, br.opts.AToBFrequency, &br.aToB, &br.stats.AToB)
+		if !br.opts.OneWay {
+			errBToA := br.forward(br.b, br.a, br.opts.BToA, br.opts.BToAFrequency, &br.bToA, &br.stats.BToA)
+			if err == nil {
+				err = errBToA
+			}
+		}
+		if err == nil {
+			backoff.reset()
+			continue
+		}
+		br.mu.Lock()
+		br.err = err
+		br.mu.Unlock()
+		if errors.Is(err, ErrWaitCanceled) {
+			return
+		}
+		select {
+		case <-br.done:
+			return
+		case <-br.after(backoff.next()):
+		}
+	}
+}
+
+// forward relays at most one packet from one side of the bridge to the other.
+// It clears the error state of both radios and returns any error
+// other than a receive timeout.
+func (br *Bridge) forward(from, to Interface, transform func([]byte) []byte, freq uint32, limit *tokenBucket, count *int) error {
+	data, _ := from.Receive(br.opts.Listen)
+	if err := from.Error(); err != nil {
+		from.SetError(nil)
+		if IsTimeout(err) {
+			return nil
+		}
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+	t := br.now()
+	br.mu.Lock()
+	if br.duplicate(data, t) {
+		br.stats.Duplicates++
+		br.mu.Unlock()
+		return nil
+	}
+	if !limit.allow(t) {
+		br.stats.Limited++
+		br.mu.Unlock()
+		return nil
+	}
+	br.mu.Unlock()
+	if transform != nil {
+		data = transform(data)
+		if data == nil {
+			return nil
+		}
+		br.mu.Lock()
+		// Remember the transformed packet too, in case it is heard on the other side.
+		br.seen[string(data)] = t
+		br.mu.Unlock()
+	}
+	SendOn(to, freq, 0, data)
+	if err := to.Error(); err != nil {
+		to.SetError(nil)
+		return err
+	}
+	br.mu.Lock()
+	*count++
+	br.mu.Unlock()
+	return nil
+}
+
+// duplicate reports whether data was seen within the window,
+// and remembers it otherwise. It must be called with br.mu held.
+func (br *Bridge) duplicate(data []byte, now time.Time) bool {
+	for k, t := range br.seen {
+		if now.Sub(t) > br.opts.Window {
+			delete(br.seen, k)
+		}
+	}
+	key := string(data)
+	if _, ok := br.seen[key]; ok {
+		return true
+	}
+	br.seen[key] = now
+	return false
+}
@@ -0,0 +1,148 @@
+package radio_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+const (
+	sensorFreq   = 433920000
+	backhaulFreq = 868300000
+	retuneFreq   = 869525000
+)
+
+func TestBridge(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    radio.BridgeOptions
+		reverse bool
+		send    [][]byte
+		want    [][]byte
+		freqs   []uint32
+		stats   radio.BridgeStats
+	}{
+		{
+			name:  "forward",
+			send:  [][]byte{{1}, {2}},
+			want:  [][]byte{{1}, {2}},
+			freqs: []uint32{backhaulFreq, backhaulFreq},
+			stats: radio.BridgeStats{AToB: 2},
+		},
+		{
+			name:    "reverse",
+			reverse: true,
+			send:    [][]byte{{1}},
+			want:    [][]byte{{1}},
+			freqs:   []uint32{sensorFreq},
+			stats:   radio.BridgeStats{BToA: 1},
+		},
+		{
+			name:  "transform",
+			opts:  radio.BridgeOptions{AToB: func(p []byte) []byte { return append(p, 0xFF) }},
+			send:  [][]byte{{1}},
+			want:  [][]byte{{1, 0xFF}},
+			freqs: []uint32{backhaulFreq},
+			stats: radio.BridgeStats{AToB: 1},
+		},
+		{
+			name:  "retune",
+			opts:  radio.BridgeOptions{AToBFrequency: retuneFreq},
+			send:  [][]byte{{1}},
+			want:  [][]byte{{1}},
+			freqs: []uint32{retuneFreq},
+			stats: radio.BridgeStats{AToB: 1},
+		},
+		{
+			name:  "duplicate",
+			send:  [][]byte{{1}, {1}},
+			want:  [][]byte{{1}},
+			freqs: []uint32{backhaulFreq},
+			stats: radio.BridgeStats{AToB: 1, Duplicates: 1},
+		},
+		{
+			name:  "rate limit",
+			opts:  radio.BridgeOptions{Rate: 1},
+			send:  [][]byte{{1}, {2}},
+			want:  [][]byte{{1}},
+			freqs: []uint32{backhaulFreq},
+			stats: radio.BridgeStats{AToB: 1, Limited: 1},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sim.NewMedium()
+			sensor, backhaul := m.NewRadio("sensor"), m.NewRadio("backhaul")
+			a, b := &recorder{Radio: m.NewRadio("a")}, &recorder{Radio: m.NewRadio("b")}
+			sensor.Init(sensorFreq)
+			a.Init(sensorFreq)
+			b.Init(backhaulFreq)
+			backhaul.Init(backhaulFreq)
+			br := radio.NewBridge(a, b, c.opts)
+			defer br.Stop()
+			from, listener, to := sensor, a, b
+			if c.reverse {
+				from, listener, to = backhaul, b, a
+			}
+			for i, p := range c.send {
+				waitFor(t, func() bool { return listener.State() == "Receive" })
+				from.Send(p)
+				waitFor(t, func() bool {
+					s := br.Stats()
+					return s.AToB+s.BToA+s.Duplicates+s.Limited > i
+				})
+			}
+			if got := to.packets(); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("forwarded %v, want %v", got, c.want)
+			}
+			if got := to.frequencies(); !reflect.DeepEqual(got, c.freqs) {
+				t.Errorf("sent on %v, want %v", got, c.freqs)
+			}
+			if got := br.Stats(); got != c.stats {
+				t.Errorf("stats = %+v, want %+v", got, c.stats)
+			}
+			if got, want := b.Frequency(), uint32(backhaulFreq); got != want {
+				t.Errorf("b left on %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestBridgeErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(r *sim.Radio)
+		want  error
+	}{
+		{"closed", func(r *sim.Radio) { r.Close() }, sim.ErrClosed},
+		{"canceled", func(r *sim.Radio) { r.SetError(radio.ErrWaitCanceled) }, radio.ErrWaitCanceled},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, b := simPair(t)
+			r := &countingRadio{Radio: a}
+			c.setup(a)
+			br := radio.NewBridge(r, b, radio.BridgeOptions{OneWay: true, Listen: time.Millisecond})
+			defer br.Stop()
+			waitFor(t, func() bool { return br.Err() != nil })
+			if err := br.Err(); !errors.Is(err, c.want) {
+				t.Errorf("Err() = %v, want %v", err, c.want)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if n := r.count(); n > 5 {
+				t.Errorf("Receive called %d times in 50ms; the loop is spinning", n)
+			}
+		})
+	}
+}
+
+func TestBridgeStopTwice(t *testing.T) {
+	_, a, b := simPair(t)
+	br := radio.NewBridge(a, b, radio.BridgeOptions{Listen: time.Millisecond})
+	br.Stop()
+	br.Stop()
+}
@@ -0,0 +1,109 @@
+package radio
+
+import (
+	"time"
+)
+
+// Calibration holds values that are expensive to re-measure,
+// so that a restarted radio can reach full performance immediately.
+// Zero fields are treated as unknown.
+type Calibration struct {
+	// Channel is the frequency the radio was last operating on, in Hertz.
+	Channel uint32 `json:"channel,omitempty"`
+	// FrequencyOffset is the offset tracked by an AFC wrapper, in Hertz.
+	FrequencyOffset int `json:"frequency_offset,omitempty"`
+	// NoiseFloor is the noise floor estimated by a Squelch wrapper, in dBm.
+	NoiseFloor int `json:"noise_floor,omitempty"`
+	// Saved is the time the calibration was stored.
+	Saved time.Time `json:"saved"`
+}
+
+// CalibrationStore loads and saves calibrations by key.
+// Load returns false if there is no calibration for the key.
+// The calstore package provides a file-backed implementation.
+type CalibrationStore interface {
+	Load(key string) (Calibration, bool, error)
+	Save(key string, c Calibration) error
+}
+
+// Calibrated wraps a radio to restore saved calibration on Init.
+// The AFC offset and noise floor are applied to AFC and Squelch
+// wrappers found in the chain, and the saved channel is used when
+// Init is called with a zero frequency. Calibrations are keyed by
+// the radio's device name.
+type Calibrated struct {
+	Interface
+
+	store CalibrationStore
+	key   string
+	clocked
+}
+
+// NewCalibrated returns a Calibrated wrapper for r that uses the given store.
+func NewCalibrated(r Interface, store CalibrationStore) *Calibrated {
+	return &Calibrated{Interface: r, store: store, key: r.Device()}
+}
+
+// WithCalibration returns middleware that restores calibration from store.
+func WithCalibration(store CalibrationStore) Middleware {
+	return func(r Interface) Interface { return NewCalibrated(r, store) }
+}
+
+// Unwrap returns the radio wrapped by c.
+func (c *Calibrated) Unwrap() Interface {
+	return c.Interface
+}
+
+// Init initializes the radio and applies the saved calibration, if any.
+// A failure to load the calibration sets the error state.
+func (c *Calibrated) Init(frequency uint32) {
+	cal, ok, err := c.store.Load(c.key)
+	if err != nil {
+		c.Interface.Init(frequency)
+		if c.Error() == nil {
+			c.SetError(err)
+		}
+		return
+	}
+	if ok && frequency == 0 {
+		frequency = cal.Channel
+	}
+	c.Interface.Init(frequency)
+	if !ok || c.Error() != nil {
+		return
+	}
+	if a, ok := Find(c.Interface, isAFC).(*AFC); ok && cal.FrequencyOffset != 0 {
+		a.SetOffset(cal.FrequencyOffset)
+	}
+	if s, ok := Find(c.Interface, isSquelch).(*Squelch); ok && cal.NoiseFloor != 0 {
+		s.SetNoiseFloor(cal.NoiseFloor)
+	}
+}
+
+// SetClock sets the Clock that timestamps saved calibrations.
+// A nil Clock means SystemClock.
+func (c *Calibrated) SetClock(clock Clock) {
+	c.clock = clock
+}
+
+// Save stores the radio's current frequency, AFC offset, and noise floor.
+func (c *Calibrated) Save() error {
+	cal := Calibration{Channel: c.Frequency(), Saved: c.now()}
+	if a, ok := Find(c.Interface, isAFC).(*AFC); ok {
+		cal.FrequencyOffset = a.Offset()
+	}
+	if s, ok := Find(c.Interface, isSquelch).(*Squelch); ok {
+		cal.NoiseFloor = s.NoiseFloor()
+	}
+	return c.store.Save(c.key, cal)
+}
+
+func isAFC(r Interface) bool {
+	_, ok := r.(*AFC)
+	return ok
+}
+
+func isSquelch(r Interface) bool {
+	_, ok := r.(*Squelch)
+	return ok
+}
@@ -0,0 +1,91 @@
+package radio_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+// memStore is an in-memory CalibrationStore.
+type memStore struct {
+	cals map[string]radio.Calibration
+	err  error
+}
+
+func (s *memStore) Load(key string) (radio.Calibration, bool, error) {
+	if s.err != nil {
+		return radio.Calibration{}, false, s.err
+	}
+	c, ok := s.cals[key]
+	return c, ok, nil
+}
+
+func (s *memStore) Save(key string, c radio.Calibration) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.cals[key] = c
+	return nil
+}
+
+func TestCalibrated(t *testing.T) {
+	const freq = 868350000
+	saved := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	errLoad := errors.New("store unavailable")
+	cases := []struct {
+		name   string
+		stored *radio.Calibration
+		err    error
+		init   uint32
+		// freq, offset, and floor are the settings after Init.
+		freq   uint32
+		offset int
+		floor  int
+	}{
+		{"nothing saved", nil, nil, freq, freq, 0, -90},
+		{"saved channel", &radio.Calibration{Channel: freq, FrequencyOffset: 700, NoiseFloor: -105}, nil, 0, freq, 700, -105},
+		{"explicit frequency", &radio.Calibration{Channel: freq, FrequencyOffset: 700, NoiseFloor: -105}, nil, 916500000, 916500000, 700, -105},
+		{"unknown fields", &radio.Calibration{Channel: freq}, nil, 0, freq, 0, -90},
+		{"load error", nil, errLoad, freq, freq, 0, -90},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			store := &memStore{cals: make(map[string]radio.Calibration), err: c.err}
+			if c.stored != nil {
+				store.cals[a.Device()] = *c.stored
+			}
+			squelch := radio.NewSquelch(a, 10)
+			squelch.SetNoiseFloor(-90)
+			afc := radio.NewAFC(squelch, 0.5, false)
+			cal := radio.NewCalibrated(afc, store)
+			cal.Init(c.init)
+			if err := cal.Error(); err != c.err {
+				t.Errorf("error = %v, want %v", err, c.err)
+			}
+			if f := cal.Frequency(); f != c.freq {
+				t.Errorf("frequency %d, want %d", f, c.freq)
+			}
+			if o := afc.Offset(); o != c.offset {
+				t.Errorf("AFC offset %d, want %d", o, c.offset)
+			}
+			if n := squelch.NoiseFloor(); n != c.floor {
+				t.Errorf("noise floor %d, want %d", n, c.floor)
+			}
+			if c.err != nil {
+				return
+			}
+			clock := radio.NewFakeClock(saved)
+			cal.SetClock(clock)
+			if err := cal.Save(); err != nil {
+				t.Fatal(err)
+			}
+			want := radio.Calibration{Channel: c.freq, FrequencyOffset: c.offset, NoiseFloor: c.floor, Saved: saved}
+			if got := store.cals[a.Device()]; got != want {
+				t.Errorf("saved %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package calstore provides a file-backed radio.CalibrationStore.
+package calstore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ecc1/radio"
+)
+
+// FileStore is a radio.CalibrationStore that keeps all calibrations
+// in a single JSON file, keyed by device.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a FileStore using the given file,
+// which is created when the first calibration is saved.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) read() (map[string]radio.Calibration, error) {
+	m := make(map[string]radio.Calibration)
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+// Load returns the calibration stored under key.
+func (s *FileStore) Load(key string) (radio.Calibration, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.read()
+	if err != nil {
+		return radio.Calibration{}, false, err
+	}
+	c, ok := m[key]
+	return c, ok, nil
+}
+
+// Save stores c under key. The file is replaced atomically,
+// so a crash while saving cannot corrupt existing calibrations.
+func (s *FileStore) Save(key string, c radio.Calibration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.read()
+	if err != nil {
+		return err
+	}
+	m[key] = c
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package calstore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/calstore"
+)
+
+func TestFileStore(t *testing.T) {
+	saved := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pump := radio.Calibration{Channel: 916500000, FrequencyOffset: -1200, NoiseFloor: -105, Saved: saved}
+	cgm := radio.Calibration{Channel: 868350000, Saved: saved}
+	cases := []struct {
+		name  string
+		saves map[string]radio.Calibration
+		key   string
+		want  radio.Calibration
+		ok    bool
+	}{
+		{"no file", nil, "pump", radio.Calibration{}, false},
+		{"round trip", map[string]radio.Calibration{"pump": pump}, "pump", pump, true},
+		{"other key", map[string]radio.Calibration{"pump": pump}, "cgm", radio.Calibration{}, false},
+		{"several keys", map[string]radio.Calibration{"pump": pump, "cgm": cgm}, "cgm", cgm, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "calstore")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "calibration.json")
+			s := calstore.NewFileStore(path)
+			for key, cal := range c.saves {
+				if err := s.Save(key, cal); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// A new store sees what was saved through the file.
+			got, ok, err := calstore.NewFileStore(path).Load(c.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != c.ok || !got.Saved.Equal(c.want.Saved) {
+				t.Fatalf("Load(%q) = %+v, %v, want %+v, %v", c.key, got, ok, c.want, c.ok)
+			}
+			got.Saved = c.want.Saved
+			if got != c.want {
+				t.Errorf("Load(%q) = %+v, want %+v", c.key, got, c.want)
+			}
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(c.saves) != 0 && len(files) != 1 {
+				t.Errorf("%d files left in the directory, want 1", len(files))
+			}
+		})
+	}
+}
+
+func TestFileStoreCorrupt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "calibration.json")
+	if err := ioutil.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := calstore.NewFileStore(path)
+	if _, _, err := s.Load("pump"); err == nil {
+		t.Error("Load succeeded on a corrupt file")
+	}
+	if err := s.Save("pump", radio.Calibration{Channel: 916500000}); err == nil {
+		t.Error("Save replaced a corrupt file")
+	}
+}
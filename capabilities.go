@@ -0,0 +1,41 @@
+package radio
+
+// Capabilities describes the optional features supported by a radio.
+type Capabilities struct {
+	Sleep           bool
+	WakeOnRadio     bool
+	RSSI            bool
+	Temperature     bool
+	RawMode         bool
+	TransmitPower   bool
+	DataRate        bool
+	MaxPacketLength int
+}
+
+// Capable is implemented by radios that report their capabilities.
+type Capable interface {
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the capabilities of r.
+// If no radio in the wrapped chain implements Capable, they are inferred
+// from the optional interfaces in this package implemented anywhere in
+// the chain, with MaxPacketLength taken from the flavor of a HardwareAccessor.
+func CapabilitiesOf(r Interface) Capabilities {
+	if c, ok := Find(r, isCapable).(Capable); ok {
+		return c.Capabilities()
+	}
+	var c Capabilities
+	c.RSSI = Find(r, isRSSIReader) != nil
+	c.TransmitPower = Find(r, isPowerController) != nil
+	c.DataRate = Find(r, isDataRater) != nil
+	if a, ok := Find(r, isHardwareAccessor).(HardwareAccessor); ok {
+		c.MaxPacketLength = a.Hardware().MaxPayloadLength()
+	}
+	return c
+}
+
+func isCapable(r Interface) bool {
+	_, ok := r.(Capable)
+	return ok
+}
@@ -0,0 +1,61 @@
+package radio
+
+import (
+	"testing"
+	"time"
+)
+
+// payloadFlavor declares a maximum payload length.
+type payloadFlavor struct{ testFlavor }
+
+func (payloadFlavor) MaxPayloadLength() int { return 64 }
+func (payloadFlavor) FIFOSize() int         { return 32 }
+
+// featureRadio adds RSSI, power, and data rate controls to a hwRadio.
+type featureRadio struct{ *hwRadio }
+
+func (r featureRadio) ReadRSSI() int        { return -90 }
+func (r featureRadio) TransmitPower() int   { return 0 }
+func (r featureRadio) SetTransmitPower(int) {}
+func (r featureRadio) DataRate() uint32     { return 9600 }
+func (r featureRadio) SetDataRate(uint32)   {}
+func (r featureRadio) Unwrap() Interface    { return r.hwRadio }
+
+// capableRadio reports its own capabilities.
+type capableRadio struct{ *hwRadio }
+
+func (r capableRadio) Capabilities() Capabilities {
+	return Capabilities{Sleep: true, MaxPacketLength: 32}
+}
+
+func (r capableRadio) Unwrap() Interface { return r.hwRadio }
+
+func TestCapabilitiesOf(t *testing.T) {
+	all := Capabilities{RSSI: true, TransmitPower: true, DataRate: true, MaxPacketLength: 64}
+	cases := []struct {
+		name  string
+		radio func(r *hwRadio) Interface
+		want  Capabilities
+	}{
+		{"hardware", func(r *hwRadio) Interface { return r }, Capabilities{MaxPacketLength: 64}},
+		{"wrapped hardware", func(r *hwRadio) Interface {
+			return NewPacketGap(r, time.Millisecond)
+		}, Capabilities{MaxPacketLength: 64}},
+		{"features", func(r *hwRadio) Interface { return featureRadio{r} }, all},
+		{"wrapped features", func(r *hwRadio) Interface {
+			return NewPacketGap(NewTraced(featureRadio{r}, nil), time.Millisecond)
+		}, all},
+		{"wrapped capable", func(r *hwRadio) Interface {
+			return NewSquelch(capableRadio{r}, 10)
+		}, Capabilities{Sleep: true, MaxPacketLength: 32}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Open(payloadFlavor{}, DryRun(nil))
+			defer h.Close()
+			if got := CapabilitiesOf(c.radio(&hwRadio{hw: h})); got != c.want {
+				t.Errorf("capabilities = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package radio
+
+// TransferLimitFlavor is implemented by flavors whose SPI controller
+// limits the length of a single transfer, including the address byte.
+type TransferLimitFlavor interface {
+	MaxTransferLength() int
+}
+
+// FIFOFlavor is implemented by flavors to identify burst addresses that
+// do not auto-increment, such as FIFOs, so that a burst split into several
+// transfers keeps using the same address instead of advancing it.
+type FIFOFlavor interface {
+	FIFO(addr byte) bool
+}
+
+// MaxTransferLength returns an Option that limits SPI transfers,
+// including the address byte, to n bytes. Longer bursts are split
+// into several transfers. It overrides any limit declared by the flavor.
+func MaxTransferLength(n int) Option {
+	return func(h *Hardware) {
+		h.maxTransfer = n
+	}
+}
+
+// chunkSize returns the number of data bytes that fit in one transfer,
+// or n if no limit applies.
+func (h *Hardware) chunkSize(n int) int {
+	max := h.maxTransfer
+	if max == 0 {
+		if f, ok := h.flavor.(TransferLimitFlavor); ok {
+			max = f.MaxTransferLength()
+		}
+	}
+	if max < 2 || n < max {
+		return n
+	}
+	return max - 1
+}
+
+// nextAddress returns the address at which the next chunk of a burst begins.
+func (h *Hardware) nextAddress(addr byte, offset int) byte {
+	if f, ok := h.flavor.(FIFOFlavor); ok && f.FIFO(addr) {
+		return addr
+	}
+	return addr + byte(offset)
+}
+
+func (h *Hardware) readChunks(addr byte, n int) []byte {
+	size := h.chunkSize(n)
+	data := make([]byte, 0, n)
+	buf := h.buffer(size + 1)
+	for len(data) < n && h.Error() == nil {
+		k := n - len(data)
+		if k > size {
+			k = size
+		}
+		a := h.nextAddress(addr, len(data))
+		buf[0] = h.flavor.ReadBurstAddress(a)
+		err := h.transfer(buf[:k+1], buf[:k+1])
+		h.complete(ReadBurstOp, a, buf[1:k+1], err)
+		data = append(data, buf[1:k+1]...)
+	}
+	return data
+}
+
+func (h *Hardware) writeChunks(addr byte, data []byte) {
+	size := h.chunkSize(len(data))
+	buf := h.buffer(size + 1)
+	for i := 0; i < len(data); i += size {
+		chunk := data[i:]
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+		a := h.nextAddress(addr, i)
+		buf[0] = h.flavor.WriteBurstAddress(a)
+		copy(buf[1:], chunk)
+		err := h.transfer(buf[:len(chunk)+1], buf[:len(chunk)+1])
+		h.complete(WriteBurstOp, a, chunk, err)
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package radio
+
+import (
+	"bytes"
+	"testing"
+)
+
+// chunkFlavor limits transfers to 4 bytes and treats address 0x00 as a FIFO.
+type chunkFlavor struct{ testFlavor }
+
+func (chunkFlavor) MaxTransferLength() int { return 4 }
+func (chunkFlavor) FIFO(addr byte) bool    { return addr == 0x00 }
+
+// chunk is the address and length of the data in one transfer of a burst.
+type chunk struct {
+	addr byte
+	n    int
+}
+
+func TestChunks(t *testing.T) {
+	cases := []struct {
+		name   string
+		flavor HardwareFlavor
+		opts   []Option
+		addr   byte
+		n      int
+		chunks []chunk
+	}{
+		{"no limit", testFlavor{}, nil, 0x10, 7, []chunk{{0x10, 7}}},
+		{"fits", testFlavor{}, []Option{MaxTransferLength(4)}, 0x10, 3, []chunk{{0x10, 3}}},
+		{"exact multiple", testFlavor{}, []Option{MaxTransferLength(4)}, 0x10, 6, []chunk{{0x10, 3}, {0x13, 3}}},
+		{"remainder", testFlavor{}, []Option{MaxTransferLength(4)}, 0x10, 7, []chunk{{0x10, 3}, {0x13, 3}, {0x16, 1}}},
+		{"limit length", testFlavor{}, []Option{MaxTransferLength(4)}, 0x10, 4, []chunk{{0x10, 3}, {0x13, 1}}},
+		{"flavor limit", chunkFlavor{}, nil, 0x10, 5, []chunk{{0x10, 3}, {0x13, 2}}},
+		{"option overrides flavor", chunkFlavor{}, []Option{MaxTransferLength(3)}, 0x10, 5, []chunk{{0x10, 2}, {0x12, 2}, {0x14, 1}}},
+		{"FIFO", chunkFlavor{}, nil, 0x00, 7, []chunk{{0x00, 3}, {0x00, 3}, {0x00, 1}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data := make([]byte, c.n)
+			for i := range data {
+				data[i] = byte(i + 1)
+			}
+			check := func(op string, addr func(byte) byte, payload bool) {
+				t.Helper()
+				h := Open(c.flavor, append([]Option{DryRun(nil)}, c.opts...)...)
+				defer h.Close()
+				if op == "write" {
+					h.WriteBurst(c.addr, data)
+				} else if got := h.ReadBurst(c.addr, c.n); len(got) != c.n {
+					t.Errorf("read %d bytes, want %d", len(got), c.n)
+				}
+				if err := h.Error(); err != nil {
+					t.Fatalf("%s: %v", op, err)
+				}
+				transfers := h.DryRunTransfers()
+				if len(transfers) != len(c.chunks) {
+					t.Fatalf("%s: %d transfers, want %d", op, len(transfers), len(c.chunks))
+				}
+				offset := 0
+				for i, x := range transfers {
+					want := c.chunks[i]
+					if x[0] != addr(want.addr) || len(x) != want.n+1 {
+						t.Errorf("%s transfer %d: address %02X, length %d, want %02X, %d", op, i, x[0], len(x)-1, addr(want.addr), want.n)
+						continue
+					}
+					if payload && !bytes.Equal(x[1:], data[offset:offset+want.n]) {
+						t.Errorf("%s transfer %d: data % X, want % X", op, i, x[1:], data[offset:offset+want.n])
+					}
+					offset += want.n
+				}
+			}
+			check("write", c.flavor.WriteBurstAddress, true)
+			check("read", c.flavor.ReadBurstAddress, false)
+		})
+	}
+}
+
+func TestChunksZeroLength(t *testing.T) {
+	h := Open(chunkFlavor{}, DryRun(nil))
+	defer h.Close()
+	if data := h.readChunks(0x10, 0); len(data) != 0 {
+		t.Errorf("readChunks returned % X", data)
+	}
+	h.writeChunks(0x10, nil)
+	if n := len(h.DryRunTransfers()); n != 0 {
+		t.Errorf("%d transfers for zero-length bursts", n)
+	}
+}
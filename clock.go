@@ -0,0 +1,110 @@
+package radio
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock provides the time to the protocol layers in this package,
+// so that time-dependent logic can be tested deterministically.
+// Each component takes its Clock from its configuration,
+// using SystemClock if none is given.
+// Waits performed by the hardware itself always use real time.
+type Clock interface {
+	Now() time.Time
+	Sleep(time.Duration)
+	After(time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SystemClock is the Clock backed by the time package.
+var SystemClock Clock = systemClock{}
+
+// clocked holds the Clock of a component; a nil Clock means SystemClock.
+type clocked struct {
+	clock Clock
+}
+
+func (c clocked) get() Clock {
+	if c.clock == nil {
+		return SystemClock
+	}
+	return c.clock
+}
+
+func (c clocked) now() time.Time                         { return c.get().Now() }
+func (c clocked) sleep(d time.Duration)                  { c.get().Sleep(d) }
+func (c clocked) after(d time.Duration) <-chan time.Time { return c.get().After(d) }
+func (c clocked) until(t time.Time) time.Duration        { return t.Sub(c.now()) }
+
+// FakeClock is a Clock whose time only changes when it is advanced.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	when time.Time
+	c    chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set to the given time.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the clock's time
+// once it has been advanced by at least d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{when: c.now.Add(d), c: ch})
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].when.Before(c.waiters[j].when)
+	})
+	return ch
+}
+
+// Sleep blocks until the clock has been advanced by at least d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Advance moves the clock forward by d, waking any sleepers whose time has come.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	i := 0
+	for ; i < len(c.waiters) && !c.waiters[i].when.After(c.now); i++ {
+		c.waiters[i].c <- c.now
+	}
+	c.waiters = c.waiters[i:]
+}
+
+// Waiters returns the number of pending sleeps and After calls,
+// so a test can wait for the code under test to block before advancing.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
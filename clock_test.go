@@ -0,0 +1,128 @@
+package radio
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	const ms = time.Millisecond
+	cases := []struct {
+		name    string
+		after   []time.Duration
+		advance time.Duration
+		fired   []bool
+	}{
+		{"none due", []time.Duration{10 * ms, 20 * ms}, 5 * ms, []bool{false, false}},
+		{"first due", []time.Duration{20 * ms, 10 * ms}, 10 * ms, []bool{false, true}},
+		{"all due", []time.Duration{10 * ms, 20 * ms}, 20 * ms, []bool{true, true}},
+		{"zero", []time.Duration{0}, 0, []bool{true}},
+		{"negative", []time.Duration{-ms}, 0, []bool{true}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clock := NewFakeClock(start)
+			var chans []<-chan time.Time
+			for _, d := range c.after {
+				chans = append(chans, clock.After(d))
+			}
+			clock.Advance(c.advance)
+			if got, want := clock.Now(), start.Add(c.advance); !got.Equal(want) {
+				t.Errorf("Now() = %v, want %v", got, want)
+			}
+			pending := 0
+			for i, ch := range chans {
+				select {
+				case <-ch:
+					if !c.fired[i] {
+						t.Errorf("After(%v) fired early", c.after[i])
+					}
+				default:
+					if c.fired[i] {
+						t.Errorf("After(%v) did not fire", c.after[i])
+					}
+					pending++
+				}
+			}
+			if n := clock.Waiters(); n != pending {
+				t.Errorf("Waiters() = %d, want %d", n, pending)
+			}
+		})
+	}
+}
+
+func TestComponentClocks(t *testing.T) {
+	// Each TDMA has its own FakeClock, so the cases can run in parallel.
+	epoch := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	const ms = time.Millisecond
+	cases := []struct {
+		name string
+		at   time.Duration
+		slot int
+	}{
+		{"slot 0", 50 * ms, 0},
+		{"slot 1", 150 * ms, 1},
+		{"slot 3", 350 * ms, 3},
+		{"next frame", 450 * ms, 0},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			clock := NewFakeClock(epoch.Add(c.at))
+			tdma, err := NewTDMA(nil, TDMAConfig{SlotLength: 100 * ms, Slots: 4, Epoch: epoch, Clock: clock}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if slot := tdma.CurrentSlot(); slot != c.slot {
+				t.Errorf("CurrentSlot() = %d, want %d", slot, c.slot)
+			}
+		})
+	}
+}
+
+func TestStubWait(t *testing.T) {
+	cases := []struct {
+		name    string
+		timeout time.Duration
+		cancel  bool
+		check   func(error) bool
+	}{
+		{"timeout", 20 * time.Millisecond, false, func(err error) bool {
+			_, ok := err.(InterruptTimeoutError)
+			return ok
+		}},
+		{"canceled", time.Minute, true, func(err error) bool {
+			return errors.Is(err, ErrWaitCanceled)
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Open(testFlavor{}, Stub())
+			defer h.Close()
+			done := make(chan error, 1)
+			start := time.Now()
+			go func() {
+				h.AwaitInterrupt(c.timeout)
+				done <- h.Error()
+			}()
+			if c.cancel {
+				time.Sleep(10 * time.Millisecond)
+				h.CancelWaits()
+			}
+			select {
+			case err := <-done:
+				if !c.check(err) {
+					t.Errorf("wait returned %v", err)
+				}
+				if !c.cancel && time.Since(start) < c.timeout {
+					t.Errorf("wait returned after %v, want at least %v", time.Since(start), c.timeout)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("stub wait did not return")
+			}
+		})
+	}
+}
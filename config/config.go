@@ -0,0 +1,92 @@
+// Package config constructs radios from settings stored in a JSON file.
+//
+// Chip drivers make themselves available by calling radio.RegisterFlavor
+// with a flavor that implements radio.DriverFlavor, typically from an
+// init function, so a program can support them with blank imports
+// and select one by name in its configuration.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ecc1/radio"
+)
+
+// Config holds the settings for a radio.
+// Zero fields, and omitted pins, keep the driver's defaults.
+type Config struct {
+	Driver       string `json:"driver"`
+	SPIDevice    string `json:"spi_device,omitempty"`
+	Speed        int    `json:"speed,omitempty"`
+	CustomCS     *int   `json:"custom_cs,omitempty"`
+	InterruptPin *int   `json:"interrupt_pin,omitempty"`
+	Frequency    uint32 `json:"frequency,omitempty"`
+	DataRate     uint32 `json:"data_rate,omitempty"`
+}
+
+// Load reads a configuration from the given JSON file.
+func Load(file string) (*Config, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes a configuration from JSON.
+func Parse(data []byte) (*Config, error) {
+	c := &Config{}
+	err := json.Unmarshal(data, c)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Overrides returns the connection settings in c.
+func (c *Config) Overrides() radio.Overrides {
+	return radio.Overrides{
+		SPIDevice:    c.SPIDevice,
+		Speed:        c.Speed,
+		CustomCS:     c.CustomCS,
+		InterruptPin: c.InterruptPin,
+	}
+}
+
+// Flavor returns flavor with its connection settings overridden by c.
+func (c *Config) Flavor(flavor radio.HardwareFlavor) radio.HardwareFlavor {
+	return radio.WithSettings(flavor, c.Overrides())
+}
+
+// Open constructs the configured radio and initializes it
+// to the configured frequency (or its current one) and data rate.
+func (c *Config) Open() (radio.Interface, error) {
+	flavor, err := radio.LookupFlavor(c.Driver)
+	if err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	driver, ok := flavor.(radio.DriverFlavor)
+	if !ok {
+		return nil, fmt.Errorf("config: radio flavor %q has no driver", c.Driver)
+	}
+	r, err := driver.OpenRadio(c.Flavor(flavor))
+	if err != nil {
+		return nil, err
+	}
+	freq := c.Frequency
+	if freq == 0 {
+		freq = r.Frequency()
+	}
+	r.Init(freq)
+	if d, ok := r.(radio.DataRater); ok && c.DataRate != 0 {
+		d.SetDataRate(c.DataRate)
+	}
+	if r.Error() != nil {
+		err = r.Error()
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
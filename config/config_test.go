@@ -0,0 +1,97 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/config"
+	"github.com/ecc1/radio/sim"
+)
+
+// simFlavor is a registered flavor whose driver opens a simulated radio
+// and records the connection settings it was given.
+type simFlavor struct {
+	opened *radio.Settings
+}
+
+func (simFlavor) SPIDevice() string              { return "/dev/spidev0.0" }
+func (simFlavor) Speed() int                     { return 1000000 }
+func (simFlavor) CustomCS() int                  { return 0 }
+func (simFlavor) InterruptPin() int              { return 24 }
+func (simFlavor) ReadSingleAddress(a byte) byte  { return a | 0x80 }
+func (simFlavor) ReadBurstAddress(a byte) byte   { return a | 0xC0 }
+func (simFlavor) WriteSingleAddress(a byte) byte { return a }
+func (simFlavor) WriteBurstAddress(a byte) byte  { return a | 0x40 }
+
+func (f simFlavor) OpenRadio(flavor radio.HardwareFlavor) (radio.Interface, error) {
+	*f.opened = radio.Settings{
+		SPIDevice:    flavor.SPIDevice(),
+		Speed:        flavor.Speed(),
+		CustomCS:     flavor.CustomCS(),
+		InterruptPin: flavor.InterruptPin(),
+	}
+	return sim.NewMedium().NewRadio("config"), nil
+}
+
+// plainFlavor is a registered flavor with no driver.
+// Its OpenRadio method hides the one of simFlavor.
+type plainFlavor struct{ simFlavor }
+
+func (plainFlavor) OpenRadio() {}
+
+var opened radio.Settings
+
+func init() {
+	radio.RegisterFlavor("config-sim", func() radio.HardwareFlavor { return simFlavor{opened: &opened} })
+	radio.RegisterFlavor("config-plain", func() radio.HardwareFlavor { return plainFlavor{} })
+}
+
+func TestOpen(t *testing.T) {
+	cases := []struct {
+		name     string
+		json     string
+		ok       bool
+		settings radio.Settings
+		freq     uint32
+	}{
+		{
+			name:     "defaults",
+			json:     `{"driver": "config-sim", "frequency": 916500000}`,
+			ok:       true,
+			settings: radio.Settings{SPIDevice: "/dev/spidev0.0", Speed: 1000000, InterruptPin: 24},
+			freq:     916500000,
+		},
+		{
+			name:     "overrides",
+			json:     `{"driver": "config-sim", "spi_device": "/dev/spidev1.0", "custom_cs": 0, "interrupt_pin": 0, "frequency": 868300000}`,
+			ok:       true,
+			settings: radio.Settings{SPIDevice: "/dev/spidev1.0", Speed: 1000000, CustomCS: 0, InterruptPin: 0},
+			freq:     868300000,
+		},
+		{name: "unknown", json: `{"driver": "config-none"}`},
+		{name: "no driver", json: `{"driver": "config-plain"}`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opened = radio.Settings{}
+			cfg, err := config.Parse([]byte(c.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := cfg.Open()
+			if (err == nil) != c.ok {
+				t.Fatalf("Open() error = %v", err)
+			}
+			if !c.ok {
+				return
+			}
+			defer r.Close()
+			if opened != c.settings {
+				t.Errorf("opened with %+v, want %+v", opened, c.settings)
+			}
+			if f := r.Frequency(); f != c.freq {
+				t.Errorf("frequency = %d, want %d", f, c.freq)
+			}
+		})
+	}
+}
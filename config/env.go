@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ecc1/radio"
+)
+
+// Environment variables consulted by FromEnv, in addition to
+// those used by radio.OverridesFromEnv.
+const (
+	EnvDriver    = "RADIO_DEVICE"
+	EnvFrequency = "RADIO_FREQUENCY"
+)
+
+// FromEnv overrides fields of c with any values specified by environment variables.
+func (c *Config) FromEnv() error {
+	o, err := radio.OverridesFromEnv()
+	if err != nil {
+		return err
+	}
+	if o.SPIDevice != "" {
+		c.SPIDevice = o.SPIDevice
+	}
+	if o.Speed != 0 {
+		c.Speed = o.Speed
+	}
+	if o.CustomCS != nil {
+		c.CustomCS = o.CustomCS
+	}
+	if o.InterruptPin != nil {
+		c.InterruptPin = o.InterruptPin
+	}
+	if v := os.Getenv(EnvDriver); v != "" {
+		c.Driver = v
+	}
+	if v := os.Getenv(EnvFrequency); v != "" {
+		f, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q", EnvFrequency, v)
+		}
+		c.Frequency = uint32(f)
+	}
+	return nil
+}
+
+// OpenFromEnv opens the radio described entirely by environment variables.
+func OpenFromEnv() (radio.Interface, error) {
+	c := &Config{}
+	err := c.FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return c.Open()
+}
@@ -0,0 +1,129 @@
+package radio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ConnOptions configures a Conn.
+type ConnOptions struct {
+	// MaxPacket is the largest packet payload the radio can send.
+	MaxPacket int
+	// Fragment splits writes larger than MaxPacket into several packets.
+	// Otherwise each Write is sent as exactly one packet, and longer
+	// writes fail with ErrWriteTooLarge.
+	Fragment bool
+	// ReadTimeout bounds how long Read waits for a packet.
+	// Zero means Read waits indefinitely.
+	ReadTimeout time.Duration
+	// Poll is the timeout passed to each call of the radio's Receive method.
+	Poll time.Duration
+}
+
+// DefaultConnOptions are used for zero MaxPacket and Poll fields of ConnOptions.
+var DefaultConnOptions = ConnOptions{
+	MaxPacket: 64,
+	Poll:      time.Second,
+}
+
+var (
+	// ErrWriteTooLarge indicates a write that does not fit in one packet.
+	ErrWriteTooLarge = errors.New("write exceeds maximum packet size")
+	// ErrReadTimeout indicates that no packet arrived within the read timeout.
+	ErrReadTimeout = errors.New("read timeout")
+)
+
+// Conn adapts a radio to io.ReadWriteCloser, so that stream-oriented
+// code can run over it. Written data is sent in packets, and Read
+// returns the contents of received packets in order of arrival.
+// Like the radio itself, a Conn must not be used by several goroutines
+// at once.
+type Conn struct {
+	radio Interface
+	opts  ConnOptions
+	buf   []byte
+}
+
+var _ io.ReadWriteCloser = (*Conn)(nil)
+
+// NewConn returns a Conn that uses r.
+func NewConn(r Interface, opts ConnOptions) *Conn {
+	if opts.MaxPacket <= 0 {
+		opts.MaxPacket = DefaultConnOptions.MaxPacket
+	}
+	if opts.Poll <= 0 {
+		opts.Poll = DefaultConnOptions.Poll
+	}
+	return &Conn{radio: r, opts: opts}
+}
+
+// Write sends p in one or more packets.
+func (c *Conn) Write(p []byte) (int, error) {
+	if len(p) > c.opts.MaxPacket && !c.opts.Fragment {
+		return 0, ErrWriteTooLarge
+	}
+	n := 0
+	for n < len(p) {
+		k := len(p) - n
+		if k > c.opts.MaxPacket {
+			k = c.opts.MaxPacket
+		}
+		c.radio.Send(p[n : n+k])
+		if err := c.radio.Error(); err != nil {
+			c.radio.SetError(nil)
+			return n, fmt.Errorf("radio send: %w", err)
+		}
+		n += k
+	}
+	return n, nil
+}
+
+// Read reads data from received packets into p.
+// A packet larger than p is returned over successive calls.
+// Receive timeouts are retried until the read timeout expires;
+// other radio errors are returned, except that a canceled wait,
+// as after CancelWaits, is reported as io.EOF.
+func (c *Conn) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	var deadline time.Time
+	if c.opts.ReadTimeout > 0 {
+		deadline = time.Now().Add(c.opts.ReadTimeout)
+	}
+	for len(c.buf) == 0 {
+		timeout := c.opts.Poll
+		if !deadline.IsZero() {
+			left := time.Until(deadline)
+			if left <= 0 {
+				return 0, ErrReadTimeout
+			}
+			if left < timeout {
+				timeout = left
+			}
+		}
+		data, _ := c.radio.Receive(timeout)
+		if err := c.radio.Error(); err != nil {
+			c.radio.SetError(nil)
+			if IsTimeout(err) {
+				continue
+			}
+			if errors.Is(err, ErrWaitCanceled) {
+				return 0, io.EOF
+			}
+			return 0, fmt.Errorf("radio receive: %w", err)
+		}
+		c.buf = data
+	}
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+// Close closes the radio.
+func (c *Conn) Close() error {
+	c.radio.Close()
+	return c.radio.Error()
+}
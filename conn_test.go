@@ -0,0 +1,84 @@
+package radio_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+func TestConnReadWrite(t *testing.T) {
+	_, a, b := simPair(t)
+	opts := radio.ConnOptions{MaxPacket: 4, Fragment: true, Poll: 10 * time.Millisecond, ReadTimeout: time.Second}
+	rx := radio.NewConn(b, opts)
+	tx := radio.NewConn(a, opts)
+	done := make(chan []byte)
+	go func() {
+		buf := make([]byte, 3)
+		var got []byte
+		for len(got) < 6 {
+			n, err := rx.Read(buf)
+			if err != nil {
+				break
+			}
+			got = append(got, buf[:n]...)
+		}
+		done <- got
+	}()
+	time.Sleep(20 * time.Millisecond)
+	msg := []byte("abcdef")
+	if _, err := tx.Write(msg[:4]); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := tx.Write(msg[4:]); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-done; !bytes.Equal(got, msg) {
+		t.Errorf("read %q, want %q", got, msg)
+	}
+}
+
+func TestConnErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		opts radio.ConnOptions
+		err  error
+	}{
+		{"ReadTimeout", radio.ConnOptions{Poll: 5 * time.Millisecond, ReadTimeout: 20 * time.Millisecond}, radio.ErrReadTimeout},
+		{"Closed", radio.ConnOptions{Poll: 5 * time.Millisecond}, sim.ErrClosed},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, b := simPair(t)
+			if c.err == sim.ErrClosed {
+				b.Close()
+			}
+			conn := radio.NewConn(b, c.opts)
+			errc := make(chan error, 1)
+			go func() {
+				_, err := conn.Read(make([]byte, 8))
+				errc <- err
+			}()
+			select {
+			case err := <-errc:
+				if !errors.Is(err, c.err) {
+					t.Errorf("Read error %v, want %v", err, c.err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Read did not return")
+			}
+		})
+	}
+}
+
+func TestConnWriteTooLarge(t *testing.T) {
+	_, a, _ := simPair(t)
+	conn := radio.NewConn(a, radio.ConnOptions{MaxPacket: 4})
+	if _, err := conn.Write(make([]byte, 5)); err != radio.ErrWriteTooLarge {
+		t.Errorf("Write error %v, want %v", err, radio.ErrWriteTooLarge)
+	}
+}
@@ -0,0 +1,51 @@
+package radio
+
+import (
+	"context"
+)
+
+// ContextIniter is implemented by radios whose initialization can be
+// bounded by a context, so that a chip stuck in calibration or a state
+// transition cannot hang the caller indefinitely.
+type ContextIniter interface {
+	InitContext(ctx context.Context, frequency uint32) error
+}
+
+// InitContext initializes r to the given frequency, giving up when ctx
+// is done. If r does not implement ContextIniter, Init runs in a separate
+// goroutine. If ctx is done first and a HardwareAccessor is in the chain,
+// its waits are canceled and InitContext returns once Init has stopped
+// using the radio; the radio should then be closed. Without a
+// HardwareAccessor, Init cannot be interrupted, so the goroutine is
+// abandoned, still running and holding a reference to r.
+func InitContext(ctx context.Context, r Interface, frequency uint32) error {
+	if i, ok := r.(ContextIniter); ok {
+		return i.InitContext(ctx, frequency)
+	}
+	done := make(chan struct{})
+	go func() {
+		r.Init(frequency)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return r.Error()
+	case <-ctx.Done():
+		if a, ok := Find(r, isHardwareAccessor).(HardwareAccessor); ok {
+			a.Hardware().CancelWaits()
+			<-done
+		}
+		return ctx.Err()
+	}
+}
+
+// SetContext sets a context that bounds the device's polling waits:
+// when it is done, they stop and set the error state to the context's
+// error. These are AwaitStateChange, and AwaitTransmit and AwaitInterrupt
+// when the chip is polled rather than waited on through the interrupt
+// line. Waits on the interrupt line are not bounded by the context;
+// use CancelWaits to stop them. Drivers can use it to implement
+// ContextIniter. A nil context removes the bound.
+func (h *Hardware) SetContext(ctx context.Context) {
+	h.ctx = ctx
+}
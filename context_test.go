@@ -0,0 +1,84 @@
+package radio_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+type stubFlavor struct{}
+
+func (stubFlavor) SPIDevice() string              { return "/dev/spidev0.0" }
+func (stubFlavor) Speed() int                     { return 1000000 }
+func (stubFlavor) CustomCS() int                  { return 0 }
+func (stubFlavor) InterruptPin() int              { return 24 }
+func (stubFlavor) ReadSingleAddress(a byte) byte  { return a | 0x80 }
+func (stubFlavor) ReadBurstAddress(a byte) byte   { return a | 0xC0 }
+func (stubFlavor) WriteSingleAddress(a byte) byte { return a }
+func (stubFlavor) WriteBurstAddress(a byte) byte  { return a | 0x40 }
+
+// slowInit is a simulated radio whose Init first waits for an
+// interrupt on a stub device, like a driver waiting for calibration.
+type slowInit struct {
+	*sim.Radio
+	hw     *radio.Hardware
+	wait   time.Duration
+	inited chan struct{}
+}
+
+func (r *slowInit) Init(frequency uint32) {
+	defer close(r.inited)
+	r.hw.AwaitInterrupt(r.wait)
+	if err := r.hw.Error(); errors.Is(err, radio.ErrWaitCanceled) {
+		r.SetError(err)
+		return
+	}
+	r.Radio.Init(frequency)
+}
+
+func (r *slowInit) Hardware() *radio.Hardware { return r.hw }
+
+func (r *slowInit) Unwrap() radio.Interface { return r.Radio }
+
+func TestInitContext(t *testing.T) {
+	cases := []struct {
+		name    string
+		wait    time.Duration
+		timeout time.Duration
+		want    error
+	}{
+		{"completes", 10 * time.Millisecond, time.Second, nil},
+		{"deadline", time.Minute, 20 * time.Millisecond, context.DeadlineExceeded},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hw := radio.Open(stubFlavor{}, radio.Stub())
+			defer hw.Close()
+			r := &slowInit{
+				Radio:  sim.NewMedium().NewRadio("r"),
+				hw:     hw,
+				wait:   c.wait,
+				inited: make(chan struct{}),
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+			defer cancel()
+			start := time.Now()
+			err := radio.InitContext(ctx, r, 916500000)
+			if !errors.Is(err, c.want) {
+				t.Errorf("InitContext returned %v, want %v", err, c.want)
+			}
+			if d := time.Since(start); d > time.Second {
+				t.Errorf("InitContext took %v", d)
+			}
+			select {
+			case <-r.inited:
+			default:
+				t.Errorf("InitContext returned while Init was still running")
+			}
+		})
+	}
+}
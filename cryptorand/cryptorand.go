@@ -0,0 +1,36 @@
+// Package cryptorand provides a math/rand.Source backed by crypto/rand,
+// for users who need random choices, such as CSMA backoff times,
+// that cannot be predicted by an observer:
+//
+//	config := radio.DefaultCSMAConfig
+//	config.Source = cryptorand.Source{}
+//
+// It is a separate package so that the radio package does not
+// link the crypto packages.
+package cryptorand
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// Source is a math/rand.Source backed by crypto/rand.
+// It cannot be seeded.
+type Source struct{}
+
+// Int63 returns a non-negative random 63-bit integer.
+func (s Source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Uint64 returns a random 64-bit integer.
+func (Source) Uint64() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Seed does nothing.
+func (Source) Seed(int64) {}
@@ -0,0 +1,237 @@
+package radio
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// CSMAConfig configures carrier-sense multiple access with collision avoidance.
+// Zero Threshold, SlotTime, MinExponent, MaxExponent, and MaxBackoffs
+// fields are replaced by the corresponding DefaultCSMAConfig values.
+type CSMAConfig struct {
+	// Threshold is the RSSI in dBm above which the channel is considered busy.
+	Threshold int
+	// SlotTime is the backoff time unit.
+	SlotTime time.Duration
+	// MinExponent and MaxExponent bound the backoff exponent: before each
+	// carrier sense, the sender waits a random number of slot times
+	// less than 2 to the power of the current exponent.
+	// MaxExponent is limited to MaxCSMAExponent.
+	MinExponent int
+	MaxExponent int
+	// MaxBackoffs is the number of times the channel may be found busy
+	// before a transmission attempt fails.
+	MaxBackoffs int
+	// MaxRetries is the number of times a packet is retransmitted
+	// when no acknowledgement is received.
+	MaxRetries int
+	// AckTimeout, if positive, makes Send wait for an acknowledgement
+	// for this long after each transmission.
+	AckTimeout time.Duration
+	// IsAck reports whether a reply acknowledges the packet that was sent.
+	// If nil, any reply is accepted.
+	IsAck func(sent, reply []byte) bool
+	// Source provides the random backoff times.
+	// If nil, a source seeded from the current time is used.
+	Source rand.Source
+	// Clock provides the time. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// DefaultCSMAConfig holds typical CSMA/CA parameters, modeled on IEEE 802.15.4.
+var DefaultCSMAConfig = CSMAConfig{
+	Threshold:   -90,
+	SlotTime:    time.Millisecond,
+	MinExponent: 3,
+	MaxExponent: 5,
+	MaxBackoffs: 4,
+	MaxRetries:  3,
+}
+
+// MaxCSMAExponent bounds the backoff exponent,
+// so that random backoffs stay within a sensible range.
+const MaxCSMAExponent = 16
+
+var (
+	// ErrChannelBusy indicates that the channel remained busy for too many backoffs.
+	ErrChannelBusy = errors.New("channel busy")
+	// ErrNoAck indicates that no acknowledgement was received after all retries.
+	ErrNoAck = errors.New("no acknowledgement")
+)
+
+// CSMAStats counts the events seen by a CSMA wrapper.
+type CSMAStats struct {
+	Sent      int
+	Deferrals int
+	Retries   int
+	Busy      int
+	NoAck     int
+}
+
+// CSMA wraps a radio so that Send and SendAndReceive listen before
+// transmitting and back off for a random time when the channel is busy
+// or an expected acknowledgement does not arrive.
+// Carrier sense requires an RSSIReader in the wrapped chain;
+// without one, the channel is always considered idle.
+type CSMA struct {
+	Interface
+	config CSMAConfig
+	rand   *rand.Rand
+	clocked
+
+	mu    sync.Mutex
+	stats CSMAStats
+}
+
+// NewCSMA returns a CSMA wrapper for r with the given configuration.
+func NewCSMA(r Interface, config CSMAConfig) *CSMA {
+	if config.Threshold == 0 {
+		config.Threshold = DefaultCSMAConfig.Threshold
+	}
+	if config.SlotTime <= 0 {
+		config.SlotTime = DefaultCSMAConfig.SlotTime
+	}
+	if config.MinExponent <= 0 {
+		config.MinExponent = DefaultCSMAConfig.MinExponent
+	}
+	if config.MaxExponent <= 0 {
+		config.MaxExponent = DefaultCSMAConfig.MaxExponent
+	}
+	if config.MaxExponent > MaxCSMAExponent {
+		config.MaxExponent = MaxCSMAExponent
+	}
+	if config.MinExponent > config.MaxExponent {
+		config.MinExponent = config.MaxExponent
+	}
+	if config.MaxBackoffs <= 0 {
+		config.MaxBackoffs = DefaultCSMAConfig.MaxBackoffs
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	return &CSMA{
+		Interface: r,
+		config:    config,
+		rand:      rand.New(NewSource(config.Source)),
+		clocked:   clocked{config.Clock},
+	}
+}
+
+// Unwrap returns the radio wrapped by c.
+func (c *CSMA) Unwrap() Interface {
+	return c.Interface
+}
+
+// Stats returns the counts of CSMA events.
+func (c *CSMA) Stats() CSMAStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *CSMA) count(f func(*CSMAStats)) {
+	c.mu.Lock()
+	f(&c.stats)
+	c.mu.Unlock()
+}
+
+// busy reports whether the channel is in use.
+func (c *CSMA) busy() bool {
+	r, ok := Find(c.Interface, isRSSIReader).(RSSIReader)
+	return ok && r.ReadRSSI() > c.config.Threshold
+}
+
+// backoff returns a random number of slot times less than 2^be.
+// The random source is shared by concurrent senders, so it is locked.
+func (c *CSMA) backoff(be int) time.Duration {
+	if be > c.config.MaxExponent {
+		be = c.config.MaxExponent
+	}
+	c.mu.Lock()
+	slots := c.rand.Intn(1 << uint(be))
+	c.mu.Unlock()
+	return time.Duration(slots) * c.config.SlotTime
+}
+
+// access waits for the channel to be idle, backing off as needed
+// starting from exponent be. It returns the exponent reached.
+func (c *CSMA) access(be int) (int, bool) {
+	for nb := 0; nb <= c.config.MaxBackoffs; nb++ {
+		c.sleep(c.backoff(be))
+		if !c.busy() {
+			return be, true
+		}
+		c.count(func(s *CSMAStats) { s.Deferrals++ })
+		if be < c.config.MaxExponent {
+			be++
+		}
+	}
+	return be, false
+}
+
+// Send transmits data using CSMA/CA. If the channel stays busy or no
+// acknowledgement arrives, the error state is set to ErrChannelBusy
+// or ErrNoAck. Each retransmission after a missed acknowledgement
+// starts its backoff with a larger exponent, up to MaxExponent.
+// Errors other than receive timeouts are left in the error state
+// and end the attempt.
+func (c *CSMA) Send(data []byte) {
+	be := c.config.MinExponent
+	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		if attempt != 0 {
+			c.count(func(s *CSMAStats) { s.Retries++ })
+			if be < c.config.MaxExponent {
+				be++
+			}
+		}
+		var ok bool
+		be, ok = c.access(be)
+		if !ok {
+			c.count(func(s *CSMAStats) { s.Busy++ })
+			c.SetError(ErrChannelBusy)
+			return
+		}
+		if c.config.AckTimeout <= 0 {
+			c.Interface.Send(data)
+			if c.Error() == nil {
+				c.count(func(s *CSMAStats) { s.Sent++ })
+			}
+			return
+		}
+		reply, _ := c.Interface.SendAndReceive(data, c.config.AckTimeout)
+		if err := c.Error(); err != nil {
+			if !IsTimeout(err) {
+				return
+			}
+			// A receive timeout just means the acknowledgement was missed.
+			c.SetError(nil)
+			continue
+		}
+		if reply != nil && (c.config.IsAck == nil || c.config.IsAck(data, reply)) {
+			c.count(func(s *CSMAStats) { s.Sent++ })
+			return
+		}
+	}
+	c.count(func(s *CSMAStats) { s.NoAck++ })
+	c.SetError(ErrNoAck)
+}
+
+// SendAndReceive transmits data once the channel is idle, backing off
+// as Send does, and then receives a reply with the given timeout.
+// The reply is not treated as an acknowledgement, so the packet is
+// sent only once. If the channel stays busy, the error state is set
+// to ErrChannelBusy.
+func (c *CSMA) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	if _, ok := c.access(c.config.MinExponent); !ok {
+		c.count(func(s *CSMAStats) { s.Busy++ })
+		c.SetError(ErrChannelBusy)
+		return nil, 0
+	}
+	reply, rssi := c.Interface.SendAndReceive(data, timeout)
+	if err := c.Error(); err == nil || IsTimeout(err) {
+		c.count(func(s *CSMAStats) { s.Sent++ })
+	}
+	return reply, rssi
+}
@@ -0,0 +1,219 @@
+package radio_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+// busyRadio reports a constant RSSI.
+type busyRadio struct {
+	*sim.Radio
+	rssi int
+}
+
+func (r busyRadio) ReadRSSI() int { return r.rssi }
+
+func (r busyRadio) Unwrap() radio.Interface { return r.Radio }
+
+// maxSource makes every backoff the longest allowed by its exponent.
+type maxSource struct{}
+
+func (maxSource) Int63() int64 { return 1<<63 - 1 }
+func (maxSource) Seed(int64)   {}
+
+func echo(r radio.Interface, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+		if p, _ := r.Receive(5 * time.Millisecond); p != nil {
+			r.Send(p)
+		}
+	}
+}
+
+func TestCSMA(t *testing.T) {
+	config := radio.DefaultCSMAConfig
+	config.SlotTime = 10 * time.Microsecond
+	withAck := config
+	withAck.AckTimeout = 20 * time.Millisecond
+	cases := []struct {
+		name   string
+		config radio.CSMAConfig
+		rssi   int
+		echo   bool
+		close  bool
+		err    error
+		stats  radio.CSMAStats
+	}{
+		{"Idle", config, -100, false, false, nil, radio.CSMAStats{Sent: 1}},
+		{"Busy", config, -50, false, false, radio.ErrChannelBusy, radio.CSMAStats{Deferrals: config.MaxBackoffs + 1, Busy: 1}},
+		{"Acked", withAck, -100, true, false, nil, radio.CSMAStats{Sent: 1}},
+		{"NoAck", withAck, -100, false, false, radio.ErrNoAck, radio.CSMAStats{Retries: withAck.MaxRetries, NoAck: 1}},
+		{"Closed", withAck, -100, false, true, sim.ErrClosed, radio.CSMAStats{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, b := simPair(t)
+			if c.echo {
+				stop := make(chan struct{})
+				defer close(stop)
+				go echo(b, stop)
+				time.Sleep(5 * time.Millisecond)
+			}
+			if c.close {
+				a.Close()
+			}
+			csma := radio.NewCSMA(busyRadio{a, c.rssi}, c.config)
+			csma.Send([]byte{1, 2, 3})
+			if err := csma.Error(); !errors.Is(err, c.err) || (c.err == nil && err != nil) {
+				t.Errorf("error = %v, want %v", err, c.err)
+			}
+			if s := csma.Stats(); s != c.stats {
+				t.Errorf("stats = %+v, want %+v", s, c.stats)
+			}
+		})
+	}
+}
+
+// TestCSMARetryBackoff checks that the backoff exponent grows
+// across retransmissions instead of restarting at MinExponent.
+func TestCSMARetryBackoff(t *testing.T) {
+	_, a, _ := simPair(t)
+	config := radio.CSMAConfig{
+		SlotTime:    5 * time.Millisecond,
+		MinExponent: 1,
+		MaxExponent: 3,
+		MaxRetries:  2,
+		AckTimeout:  time.Millisecond,
+		Source:      maxSource{},
+	}
+	csma := radio.NewCSMA(a, config)
+	start := time.Now()
+	csma.Send([]byte{1})
+	elapsed := time.Since(start)
+	// Backoffs of 1, 3, and 7 slots; without growth it would be 3 slots.
+	if min := 11 * config.SlotTime; elapsed < min {
+		t.Errorf("Send took %v, want at least %v", elapsed, min)
+	}
+	if csma.Error() != radio.ErrNoAck {
+		t.Errorf("error = %v, want %v", csma.Error(), radio.ErrNoAck)
+	}
+}
+
+func TestCSMAConcurrentSend(t *testing.T) {
+	_, a, _ := simPair(t)
+	config := radio.DefaultCSMAConfig
+	config.SlotTime = time.Microsecond
+	csma := radio.NewCSMA(a, config)
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		go func() {
+			for j := 0; j < 20; j++ {
+				csma.Send([]byte{1})
+			}
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+	if s := csma.Stats(); s.Sent != 80 {
+		t.Errorf("sent %d, want 80", s.Sent)
+	}
+}
+
+func TestCSMASendAndReceive(t *testing.T) {
+	config := radio.DefaultCSMAConfig
+	config.SlotTime = 10 * time.Microsecond
+	cases := []struct {
+		name  string
+		rssi  int
+		echo  bool
+		reply bool
+		err   error
+		stats radio.CSMAStats
+	}{
+		{"Idle", -100, true, true, nil, radio.CSMAStats{Sent: 1}},
+		{"NoReply", -100, false, false, nil, radio.CSMAStats{Sent: 1}},
+		{"Busy", -50, true, false, radio.ErrChannelBusy, radio.CSMAStats{Deferrals: config.MaxBackoffs + 1, Busy: 1}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, b := simPair(t)
+			if c.echo {
+				stop := make(chan struct{})
+				defer close(stop)
+				go echo(b, stop)
+				waitFor(t, func() bool { return b.State() == "Receive" })
+			}
+			csma := radio.NewCSMA(busyRadio{a, c.rssi}, config)
+			reply, _ := csma.SendAndReceive([]byte{1, 2, 3}, 50*time.Millisecond)
+			if (reply != nil) != c.reply {
+				t.Errorf("reply = %v, want reply %v", reply, c.reply)
+			}
+			if err := csma.Error(); err != c.err {
+				t.Errorf("error = %v, want %v", err, c.err)
+			}
+			if s := csma.Stats(); s != c.stats {
+				t.Errorf("stats = %+v, want %+v", s, c.stats)
+			}
+		})
+	}
+}
+
+// sleepRecorder is a Clock that records sleeps instead of waiting.
+type sleepRecorder struct {
+	mu     sync.Mutex
+	sleeps []time.Duration
+}
+
+func (c *sleepRecorder) Now() time.Time { return time.Now() }
+
+func (c *sleepRecorder) Sleep(d time.Duration) {
+	c.mu.Lock()
+	c.sleeps = append(c.sleeps, d)
+	c.mu.Unlock()
+}
+
+func (c *sleepRecorder) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// TestCSMAConfigDefaults checks the longest backoffs taken on a busy
+// channel, to see that defaults are applied and the exponent is clamped.
+func TestCSMAConfigDefaults(t *testing.T) {
+	const ns = time.Nanosecond
+	max := time.Duration(1<<radio.MaxCSMAExponent-1) * ns
+	cases := []struct {
+		name   string
+		config radio.CSMAConfig
+		sleeps []time.Duration
+	}{
+		{"zero", radio.CSMAConfig{}, []time.Duration{7 * time.Millisecond, 15 * time.Millisecond, 31 * time.Millisecond, 31 * time.Millisecond, 31 * time.Millisecond}},
+		{"huge exponent", radio.CSMAConfig{Threshold: -90, SlotTime: ns, MinExponent: 100, MaxExponent: 100, MaxBackoffs: 1}, []time.Duration{max, max}},
+		{"min above max", radio.CSMAConfig{SlotTime: ns, MinExponent: 8, MaxExponent: 2, MaxBackoffs: 1}, []time.Duration{3 * ns, 3 * ns}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			clock := &sleepRecorder{}
+			c.config.Source = maxSource{}
+			c.config.Clock = clock
+			csma := radio.NewCSMA(busyRadio{a, -50}, c.config)
+			csma.Send([]byte{1})
+			if err := csma.Error(); err != radio.ErrChannelBusy {
+				t.Errorf("error = %v, want %v", err, radio.ErrChannelBusy)
+			}
+			if !reflect.DeepEqual(clock.sleeps, c.sleeps) {
+				t.Errorf("backoffs = %v, want %v", clock.sleeps, c.sleeps)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Package decode turns received packets into structured events
+// using protocol decoders that register themselves by name,
+// typically from an init function, in the style of rtl_433.
+package decode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+// Decoder recognizes and parses packets of a particular protocol,
+// such as a weather station or tire pressure sensor.
+type Decoder struct {
+	Name string
+	// Match reports whether the packet could belong to this protocol.
+	Match func(radio.Packet) bool
+	// Parse extracts the fields of a matching packet.
+	Parse func(radio.Packet) (map[string]interface{}, error)
+}
+
+// Event is the result of decoding a packet.
+type Event struct {
+	Time      time.Time              `json:"time"`
+	Decoder   string                 `json:"decoder"`
+	Frequency uint32                 `json:"frequency,omitempty"`
+	RSSI      int                    `json:"rssi"`
+	Fields    map[string]interface{} `json:"fields"`
+}
+
+var (
+	decodersMu sync.Mutex
+	decoders   = make(map[string]Decoder)
+)
+
+// Register makes a decoder available.
+// It panics if a decoder with the same name is already registered.
+func Register(d Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	if _, dup := decoders[d.Name]; dup {
+		panic("decode: Register called twice for decoder " + d.Name)
+	}
+	decoders[d.Name] = d
+}
+
+// Decoders returns the names of the registered decoders in sorted order.
+func Decoders() []string {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	var names []string
+	for name := range decoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseError records a decoder that matched a packet but failed to parse it.
+type ParseError struct {
+	Decoder string
+	Err     error
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Decoder, e.Err)
+}
+
+// Decode applies every registered decoder, in name order, to p.
+// It returns an event for each decoder that parsed the packet,
+// and a ParseError for the first one that matched but failed.
+func Decode(p radio.Packet) ([]Event, error) {
+	decodersMu.Lock()
+	var ds []Decoder
+	for _, d := range decoders {
+		ds = append(ds, d)
+	}
+	decodersMu.Unlock()
+	sort.Slice(ds, func(i, j int) bool { return ds[i].Name < ds[j].Name })
+	var events []Event
+	var err error
+	for _, d := range ds {
+		if !d.Match(p) {
+			continue
+		}
+		fields, e := d.Parse(p)
+		if e != nil {
+			if err == nil {
+				err = ParseError{Decoder: d.Name, Err: e}
+			}
+			continue
+		}
+		events = append(events, Event{
+			Time:      p.Time,
+			Decoder:   d.Name,
+			Frequency: p.Frequency,
+			RSSI:      p.RSSI,
+			Fields:    fields,
+		})
+	}
+	return events, err
+}
+
+// Writer writes events as a stream of JSON objects, one per line.
+type Writer struct {
+	enc *json.Encoder
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{enc: json.NewEncoder(w)}
+}
+
+// Write writes the given events.
+func (w *Writer) Write(events []Event) error {
+	for _, e := range events {
+		err := w.enc.Encode(e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package decode_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/decode"
+)
+
+var errShort = errors.New("short packet")
+
+func init() {
+	// A sensor reporting a temperature in tenths of a degree after a 'T' tag.
+	decode.Register(decode.Decoder{
+		Name:  "test-temp",
+		Match: func(p radio.Packet) bool { return len(p.Data) != 0 && p.Data[0] == 'T' },
+		Parse: func(p radio.Packet) (map[string]interface{}, error) {
+			if len(p.Data) != 3 {
+				return nil, errShort
+			}
+			t := int16(p.Data[1])<<8 | int16(p.Data[2])
+			return map[string]interface{}{"temperature": float64(t) / 10}, nil
+		},
+	})
+	// Every packet has a length.
+	decode.Register(decode.Decoder{
+		Name:  "test-length",
+		Match: func(p radio.Packet) bool { return true },
+		Parse: func(p radio.Packet) (map[string]interface{}, error) {
+			return map[string]interface{}{"length": float64(len(p.Data))}, nil
+		},
+	})
+}
+
+func TestDecode(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := func(name string, fields map[string]interface{}) decode.Event {
+		return decode.Event{Time: at, Decoder: name, Frequency: 433920000, RSSI: -60, Fields: fields}
+	}
+	cases := []struct {
+		name   string
+		data   []byte
+		events []decode.Event
+		err    error
+	}{
+		{"temperature", []byte{'T', 0x00, 0xE7}, []decode.Event{
+			event("test-length", map[string]interface{}{"length": 3.0}),
+			event("test-temp", map[string]interface{}{"temperature": 23.1}),
+		}, nil},
+		{"negative temperature", []byte{'T', 0xFF, 0x9C}, []decode.Event{
+			event("test-length", map[string]interface{}{"length": 3.0}),
+			event("test-temp", map[string]interface{}{"temperature": -10.0}),
+		}, nil},
+		{"other protocol", []byte{'X', 1}, []decode.Event{
+			event("test-length", map[string]interface{}{"length": 2.0}),
+		}, nil},
+		{"malformed", []byte{'T', 0x00}, []decode.Event{
+			event("test-length", map[string]interface{}{"length": 2.0}),
+		}, decode.ParseError{Decoder: "test-temp", Err: errShort}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := radio.Packet{Data: c.data, RSSI: -60, Frequency: 433920000, Time: at}
+			events, err := decode.Decode(p)
+			if err != c.err {
+				t.Errorf("error = %v, want %v", err, c.err)
+			}
+			if !reflect.DeepEqual(events, c.events) {
+				t.Fatalf("events = %+v, want %+v", events, c.events)
+			}
+			var buf bytes.Buffer
+			if err := decode.NewWriter(&buf).Write(events); err != nil {
+				t.Fatal(err)
+			}
+			dec := json.NewDecoder(&buf)
+			var got []decode.Event
+			for {
+				var e decode.Event
+				err := dec.Decode(&e)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, e)
+			}
+			if !reflect.DeepEqual(got, c.events) {
+				t.Errorf("JSON round trip = %+v, want %+v", got, c.events)
+			}
+		})
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a duplicate decoder did not panic")
+		}
+	}()
+	decode.Register(decode.Decoder{Name: "test-temp"})
+}
+
+func TestDecoders(t *testing.T) {
+	want := []string{"test-length", "test-temp"}
+	if got := decode.Decoders(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Decoders() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package radio
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// RegisterListFlavor is implemented by flavors that list the
+// registers to include in a diagnostics report. Only registers
+// that can be read without side effects should be listed.
+type RegisterListFlavor interface {
+	Registers() []byte
+}
+
+// Report is a snapshot of a radio's condition, suitable for
+// attaching to bug reports when serialized as JSON.
+// Hardware details are included when a HardwareAccessor is in the
+// wrapped chain, and recent packets when a PacketLog is.
+type Report struct {
+	Time         time.Time    `json:"time"`
+	Name         string       `json:"name"`
+	Device       string       `json:"device"`
+	State        string       `json:"state"`
+	Frequency    uint32       `json:"frequency"`
+	Wrappers     []string     `json:"wrappers,omitempty"`
+	Capabilities Capabilities `json:"capabilities"`
+	Error        string       `json:"error,omitempty"`
+	ErrorDetail  string       `json:"error_detail,omitempty"`
+
+	Settings  *Settings        `json:"settings,omitempty"`
+	Timeouts  *Timeouts        `json:"timeouts,omitempty"`
+	Stats     *Stats           `json:"stats,omitempty"`
+	Registers []RegisterReport `json:"registers,omitempty"`
+	History   []OpReport       `json:"history,omitempty"`
+	Packets   []PacketReport   `json:"packets,omitempty"`
+}
+
+// RegisterReport is the value of a register in a Report.
+type RegisterReport struct {
+	Addr  string `json:"addr"`
+	Value string `json:"value"`
+}
+
+// OpReport is a register operation in a Report.
+type OpReport struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Addr   string    `json:"addr"`
+	Data   string    `json:"data"`
+	Length int       `json:"length"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// PacketReport is a logged packet in a Report.
+type PacketReport struct {
+	Time      time.Time `json:"time"`
+	Sent      bool      `json:"sent"`
+	Frequency uint32    `json:"frequency"`
+	RSSI      int       `json:"rssi,omitempty"`
+	Data      string    `json:"data"`
+}
+
+// Diagnostics returns a snapshot of the state of r.
+// Reading the registers listed by the flavor does not disturb
+// the error state or the register history included in the report.
+func Diagnostics(r Interface) Report {
+	rep := Report{
+		Time:         time.Now(),
+		Name:         r.Name(),
+		Device:       r.Device(),
+		State:        r.State(),
+		Frequency:    r.Frequency(),
+		Capabilities: CapabilitiesOf(r),
+		ErrorDetail:  ErrorDetail(r),
+	}
+	if err := r.Error(); err != nil {
+		rep.Error = err.Error()
+	}
+	for w := r; w != nil; w = Unwrap(w) {
+		rep.Wrappers = append(rep.Wrappers, fmt.Sprintf("%T", w))
+	}
+	if a, ok := Find(r, isHardwareAccessor).(HardwareAccessor); ok {
+		h := a.Hardware()
+		settings, timeouts, stats := h.Settings(), h.Timeouts(), h.Stats()
+		rep.Settings, rep.Timeouts, rep.Stats = &settings, &timeouts, &stats
+		for _, op := range h.History() {
+			rep.History = append(rep.History, opReport(op))
+		}
+		rep.Registers = h.dumpRegisters()
+	}
+	if l, ok := Find(r, isPacketLog).(*PacketLog); ok {
+		for _, p := range l.Packets() {
+			rep.Packets = append(rep.Packets, PacketReport{
+				Time:      p.Time,
+				Sent:      p.Sent,
+				Frequency: p.Frequency,
+				RSSI:      p.RSSI,
+				Data:      hex.EncodeToString(p.Data),
+			})
+		}
+	}
+	return rep
+}
+
+func opReport(op RegisterOp) OpReport {
+	rep := OpReport{
+		Time:   op.Time,
+		Kind:   op.Kind.String(),
+		Addr:   fmt.Sprintf("%02X", op.Addr),
+		Data:   hex.EncodeToString(op.Data),
+		Length: op.Length,
+	}
+	if op.Err != nil {
+		rep.Error = op.Err.Error()
+	}
+	return rep
+}
+
+// dumpRegisters reads the registers listed by the flavor,
+// preserving the error state and register history.
+func (h *Hardware) dumpRegisters() []RegisterReport {
+	f, ok := h.flavor.(RegisterListFlavor)
+	if !ok || h.isDryRun() {
+		return nil
+	}
+	err, hist := h.err, h.history
+	h.history = history{}
+	h.err = nil
+	var regs []RegisterReport
+	for _, addr := range f.Registers() {
+		v := h.ReadRegister(addr)
+		if h.Error() != nil {
+			break
+		}
+		regs = append(regs, RegisterReport{
+			Addr:  fmt.Sprintf("%02X", addr),
+			Value: fmt.Sprintf("%02X", v),
+		})
+	}
+	h.err, h.history = err, hist
+	return regs
+}
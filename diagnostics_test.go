@@ -0,0 +1,82 @@
+package radio
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// registerFlavor lists registers for diagnostics and declares a payload limit.
+type registerFlavor struct{ payloadFlavor }
+
+func (registerFlavor) InterruptPin() int { return -1 }
+func (registerFlavor) Registers() []byte { return []byte{0x01, 0x2F} }
+
+func TestDiagnostics(t *testing.T) {
+	errFailed := errors.New("receive failed")
+	cases := []struct {
+		name      string
+		flavor    HardwareFlavor
+		err       error
+		registers []RegisterReport
+	}{
+		{"registers", registerFlavor{}, nil, []RegisterReport{{"01", "5A"}, {"2F", "5A"}}},
+		{"error state", registerFlavor{}, errFailed, []RegisterReport{{"01", "5A"}, {"2F", "5A"}}},
+		{"no register list", closeFlavor{}, nil, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Open(c.flavor, func(h *Hardware) { h.device = &statusDevice{status: 0x5A} })
+			defer h.Close()
+			h.WriteRegister(0x10, 0x01)
+			h.SetError(c.err)
+			log := NewPacketLog(&hwRadio{hw: h}, 4)
+			log.add([]byte{0xA5}, -70, false)
+			r := NewPacketGap(log, 0)
+			history := h.History()
+			rep := Diagnostics(r)
+			if h.Error() != c.err {
+				t.Errorf("error state changed to %v", h.Error())
+			}
+			if got := h.History(); !reflect.DeepEqual(got, history) {
+				t.Errorf("history changed to %v", got)
+			}
+			if !reflect.DeepEqual(rep.Registers, c.registers) {
+				t.Errorf("registers = %v, want %v", rep.Registers, c.registers)
+			}
+			if want := []string{"*radio.PacketGap", "*radio.PacketLog", "*radio.hwRadio"}; !reflect.DeepEqual(rep.Wrappers, want) {
+				t.Errorf("wrappers = %v, want %v", rep.Wrappers, want)
+			}
+			// Capabilities are found through the wrappers.
+			if want := CapabilitiesOf(&hwRadio{hw: h}); rep.Capabilities != want {
+				t.Errorf("capabilities = %+v, want %+v", rep.Capabilities, want)
+			}
+			if rep.Settings == nil || rep.Stats == nil || rep.Timeouts == nil {
+				t.Error("hardware details missing")
+			}
+			if len(rep.History) != len(history) {
+				t.Errorf("%d operations in report, want %d", len(rep.History), len(history))
+			}
+			if len(rep.Packets) != 1 || rep.Packets[0].Data != "a5" || rep.Packets[0].RSSI != -70 {
+				t.Errorf("packets = %+v", rep.Packets)
+			}
+			if (rep.Error != "") != (c.err != nil) || (rep.ErrorDetail != "") != (c.err != nil) {
+				t.Errorf("error %q, detail %q for error state %v", rep.Error, rep.ErrorDetail, c.err)
+			}
+			data, err := json.Marshal(rep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded map[string]interface{}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range []string{"capabilities", "settings", "stats", "history", "packets"} {
+				if _, ok := decoded[key]; !ok {
+					t.Errorf("JSON report has no %q field", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// Package radio provides the hardware layer and common interface
+// shared by drivers for SPI radio modules, together with wrappers
+// that add behavior such as CSMA, rate limiting, and squelch.
+//
+// The package depends only on the standard library and on the gpio,
+// spi, and x/sys packages it needs to drive the hardware, so that
+// binaries cross-compiled for small ARM targets stay small.
+// Subsystems that need heavier dependencies live in subpackages:
+//
+//	calstore    file-backed storage for calibration values (encoding/json)
+//	config      JSON configuration files and driver registration
+//	cryptorand  unpredictable random source for backoff (crypto/rand)
+//	decode      protocol decoders for received packets
+//	sim         simulated radios and radio medium for tests
+//	tap         UDP packet capture and injection (net)
+//	waterfall   CSV and PNG export of RSSI waterfalls (image/png)
+package radio
@@ -0,0 +1,83 @@
+package radio
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// dryRunDevice records SPI transfers instead of performing them.
+// Every byte read back is zero. A stub device, used on platforms
+// without SPI support, discards transfers instead of recording them.
+type dryRunDevice struct {
+	w    io.Writer
+	stub bool
+
+	mu        sync.Mutex
+	transfers [][]byte
+}
+
+func (d *dryRunDevice) Transfer(snd, rcv []byte) error {
+	buf := append([]byte(nil), snd...)
+	if !d.stub {
+		d.mu.Lock()
+		d.transfers = append(d.transfers, buf)
+		d.mu.Unlock()
+	}
+	if d.w != nil {
+		fmt.Fprintf(d.w, "SPI % X\n", buf)
+	}
+	for i := range rcv {
+		rcv[i] = 0
+	}
+	return nil
+}
+
+func (d *dryRunDevice) SetMaxSpeed(int) error { return nil }
+
+func (d *dryRunDevice) Close() error { return nil }
+
+// DryRun returns an Option that opens the device without touching
+// any hardware. SPI transfers are recorded and, if w is not nil,
+// written to it, one per line; reads return zeros and interrupt
+// waits time out immediately.
+func DryRun(w io.Writer) Option {
+	return func(h *Hardware) {
+		h.device = &dryRunDevice{w: w}
+	}
+}
+
+// Stub returns an Option that opens the device as a stub, as is done
+// automatically on platforms other than Linux: like DryRun, it touches
+// no hardware, but transfers are discarded rather than recorded and
+// interrupt waits sleep for their timeout, so that an application's
+// other code paths can be exercised without a radio module attached.
+func Stub() Option {
+	return func(h *Hardware) {
+		h.device = &dryRunDevice{stub: true}
+	}
+}
+
+// isStub reports whether the device was opened as a stub
+// on a platform without SPI support.
+func (h *Hardware) isStub() bool {
+	d, ok := h.device.(*dryRunDevice)
+	return ok && d.stub
+}
+
+func (h *Hardware) isDryRun() bool {
+	_, ok := h.device.(*dryRunDevice)
+	return ok
+}
+
+// DryRunTransfers returns the byte sequences sent to a device opened
+// with the DryRun option, in order, or nil for a real device.
+func (h *Hardware) DryRunTransfers() [][]byte {
+	d, ok := h.device.(*dryRunDevice)
+	if !ok {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([][]byte(nil), d.transfers...)
+}
@@ -0,0 +1,66 @@
+package radio
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables consulted by OverridesFromEnv.
+const (
+	EnvSPIDevice    = "RADIO_SPI"
+	EnvSPISpeed     = "RADIO_SPI_SPEED"
+	EnvCustomCS     = "RADIO_CS_PIN"
+	EnvInterruptPin = "RADIO_IRQ_PIN"
+)
+
+// OverridesFromEnv returns the connection settings specified by
+// environment variables. Unset variables leave the corresponding
+// fields empty, so the flavor's own values are used.
+func OverridesFromEnv() (Overrides, error) {
+	o := Overrides{SPIDevice: os.Getenv(EnvSPIDevice)}
+	var err error
+	if o.Speed, err = envInt(EnvSPISpeed); err != nil {
+		return o, err
+	}
+	if o.CustomCS, err = envPin(EnvCustomCS); err != nil {
+		return o, err
+	}
+	o.InterruptPin, err = envPin(EnvInterruptPin)
+	return o, err
+}
+
+func envInt(name string) (int, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", name, v)
+	}
+	return n, nil
+}
+
+// envPin is like envInt, but returns nil if the variable is unset,
+// so that pin 0 can be selected.
+func envPin(name string) (*int, error) {
+	if os.Getenv(name) == "" {
+		return nil, nil
+	}
+	n, err := envInt(name)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// OpenFromEnv opens the radio module described by the given flavor,
+// with its connection settings overridden by environment variables.
+func OpenFromEnv(flavor HardwareFlavor, options ...Option) *Hardware {
+	o, err := OverridesFromEnv()
+	if err != nil {
+		return &Hardware{flavor: flavor, err: err}
+	}
+	return Open(WithSettings(flavor, o), options...)
+}
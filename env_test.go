@@ -0,0 +1,40 @@
+package radio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverridesFromEnv(t *testing.T) {
+	cases := []struct {
+		name string
+		env  map[string]string
+		want Overrides
+		ok   bool
+	}{
+		{"unset", nil, Overrides{}, true},
+		{
+			"all",
+			map[string]string{EnvSPIDevice: "/dev/spidev1.0", EnvSPISpeed: "4000000", EnvCustomCS: "8", EnvInterruptPin: "25"},
+			Overrides{SPIDevice: "/dev/spidev1.0", Speed: 4000000, CustomCS: Pin(8), InterruptPin: Pin(25)},
+			true,
+		},
+		{"pin 0", map[string]string{EnvCustomCS: "0", EnvInterruptPin: "0"}, Overrides{CustomCS: Pin(0), InterruptPin: Pin(0)}, true},
+		{"bad speed", map[string]string{EnvSPISpeed: "fast"}, Overrides{}, false},
+		{"bad pin", map[string]string{EnvInterruptPin: "gpio24"}, Overrides{}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, name := range []string{EnvSPIDevice, EnvSPISpeed, EnvCustomCS, EnvInterruptPin} {
+				t.Setenv(name, c.env[name])
+			}
+			o, err := OverridesFromEnv()
+			if (err == nil) != c.ok {
+				t.Fatalf("OverridesFromEnv() error = %v", err)
+			}
+			if c.ok && !reflect.DeepEqual(o, c.want) {
+				t.Errorf("OverridesFromEnv() = %+v, want %+v", o, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package radio
+
+// FIFOStatusFlavor is implemented by flavors whose chips report
+// FIFO errors in a status register. The FIFO has overflowed if any
+// of the overflow bits are set, and underflowed if any of the
+// underflow bits are set.
+type FIFOStatusFlavor interface {
+	FIFOStatus() (addr byte, overflow byte, underflow byte)
+}
+
+// FIFOError describes a FIFO error detected by CheckFIFO.
+type FIFOError struct {
+	Overflow  bool
+	Underflow bool
+}
+
+func (e FIFOError) Error() string {
+	switch {
+	case e.Overflow && e.Underflow:
+		return "receive FIFO overflow and transmit FIFO underflow"
+	case e.Overflow:
+		return "receive FIFO overflow"
+	default:
+		return "transmit FIFO underflow"
+	}
+}
+
+// SetFIFORecovery sets a function, normally supplied by the chip driver,
+// that flushes the FIFOs and returns the chip to receive mode.
+// When set, and the flavor implements FIFOStatusFlavor, interrupt waits
+// that time out check for FIFO errors and call it automatically.
+func (h *Hardware) SetFIFORecovery(recovery func()) {
+	h.recoverFIFO = recovery
+}
+
+// SetFIFOObserver sets a function that is called with each FIFO error
+// detected by CheckFIFO, before any recovery, so that applications can
+// log or count overflows and underflows as they happen. Like a recovery
+// function, it makes interrupt waits that time out check for FIFO errors.
+func (h *Hardware) SetFIFOObserver(observe func(FIFOError)) {
+	h.observeFIFO = observe
+}
+
+// CheckFIFO checks the chip for FIFO errors, counts them in the device's
+// Stats, reports them to the observer, and calls the recovery function
+// if one has been set.
+// It returns the FIFOError, or nil if none was found or the flavor
+// does not implement FIFOStatusFlavor.
+func (h *Hardware) CheckFIFO() error {
+	f, ok := h.flavor.(FIFOStatusFlavor)
+	if !ok || h.Error() != nil {
+		return nil
+	}
+	addr, overflow, underflow := f.FIFOStatus()
+	status := h.ReadRegister(addr)
+	if h.Error() != nil {
+		return nil
+	}
+	e := FIFOError{Overflow: status&overflow != 0, Underflow: status&underflow != 0}
+	if !e.Overflow && !e.Underflow {
+		return nil
+	}
+	if e.Overflow {
+		h.stats.Overflows++
+	}
+	if e.Underflow {
+		h.stats.Underflows++
+	}
+	if h.observeFIFO != nil {
+		h.observeFIFO(e)
+	}
+	if h.recoverFIFO != nil {
+		h.recoverFIFO()
+		h.stats.Recoveries++
+	}
+	return e
+}
@@ -0,0 +1,121 @@
+package radio
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	fifoStatusAddr = 0x30
+	overflowBit    = 0x01
+	underflowBit   = 0x02
+)
+
+type fifoFlavor struct{ testFlavor }
+
+func (fifoFlavor) InterruptPin() int { return -1 }
+
+func (fifoFlavor) FIFOStatus() (byte, byte, byte) {
+	return fifoStatusAddr, overflowBit, underflowBit
+}
+
+// statusDevice is an SPI device whose registers all read as status.
+type statusDevice struct {
+	status byte
+}
+
+func (d *statusDevice) Transfer(snd, rcv []byte) error {
+	for i := range rcv {
+		rcv[i] = d.status
+	}
+	return nil
+}
+
+func (d *statusDevice) SetMaxSpeed(int) error { return nil }
+
+func (d *statusDevice) Close() error { return nil }
+
+func TestCheckFIFO(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   byte
+		recovery bool
+		observer bool
+		want     *FIFOError
+	}{
+		{"clear", 0, true, true, nil},
+		{"overflow", overflowBit, true, true, &FIFOError{Overflow: true}},
+		{"underflow", underflowBit, false, true, &FIFOError{Underflow: true}},
+		{"both", overflowBit | underflowBit, true, false, &FIFOError{Overflow: true, Underflow: true}},
+		{"unobserved", overflowBit, false, false, &FIFOError{Overflow: true}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Open(fifoFlavor{}, func(h *Hardware) { h.device = &statusDevice{status: c.status} })
+			defer h.Close()
+			recoveries := 0
+			var observed []FIFOError
+			if c.recovery {
+				h.SetFIFORecovery(func() { recoveries++ })
+			}
+			if c.observer {
+				h.SetFIFOObserver(func(e FIFOError) { observed = append(observed, e) })
+			}
+			err := h.CheckFIFO()
+			if c.want == nil {
+				if err != nil {
+					t.Errorf("CheckFIFO() = %v, want nil", err)
+				}
+			} else if err != *c.want {
+				t.Errorf("CheckFIFO() = %v, want %v", err, *c.want)
+			}
+			wantObserved := 0
+			if c.observer && c.want != nil {
+				wantObserved = 1
+			}
+			if len(observed) != wantObserved || (wantObserved == 1 && observed[0] != *c.want) {
+				t.Errorf("observed %v", observed)
+			}
+			wantRecoveries := 0
+			if c.recovery && c.want != nil {
+				wantRecoveries = 1
+			}
+			if recoveries != wantRecoveries {
+				t.Errorf("recovered %d times, want %d", recoveries, wantRecoveries)
+			}
+		})
+	}
+}
+
+func TestFIFOCheckOnTimeout(t *testing.T) {
+	cases := []struct {
+		name     string
+		recovery bool
+		observer bool
+		checked  bool
+	}{
+		{"neither", false, false, false},
+		{"recovery", true, false, true},
+		{"observer", false, true, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Open(fifoFlavor{}, func(h *Hardware) { h.device = &statusDevice{status: overflowBit} })
+			defer h.Close()
+			h.SetPollInterval(time.Millisecond)
+			if c.recovery {
+				h.SetFIFORecovery(func() {})
+			}
+			if c.observer {
+				h.SetFIFOObserver(func(FIFOError) {})
+			}
+			h.AwaitInterrupt(5 * time.Millisecond)
+			if _, ok := h.Error().(InterruptTimeoutError); !ok {
+				t.Errorf("error = %v, want an interrupt timeout", h.Error())
+			}
+			if got := h.Stats().Overflows > 0; got != c.checked {
+				t.Errorf("FIFO checked = %v, want %v", got, c.checked)
+			}
+		})
+	}
+}
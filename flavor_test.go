@@ -0,0 +1,17 @@
+package radio
+
+// testFlavor is a flavor for a hypothetical chip, used with the DryRun option.
+type testFlavor struct{}
+
+func (testFlavor) SPIDevice() string              { return "/dev/spidev0.0" }
+func (testFlavor) Speed() int                     { return 1000000 }
+func (testFlavor) CustomCS() int                  { return 0 }
+func (testFlavor) InterruptPin() int              { return 24 }
+func (testFlavor) ReadSingleAddress(a byte) byte  { return a | 0x80 }
+func (testFlavor) ReadBurstAddress(a byte) byte   { return a | 0xC0 }
+func (testFlavor) WriteSingleAddress(a byte) byte { return a }
+func (testFlavor) WriteBurstAddress(a byte) byte  { return a | 0x40 }
+
+func openTest(options ...Option) *Hardware {
+	return Open(testFlavor{}, append([]Option{DryRun(nil)}, options...)...)
+}
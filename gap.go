@@ -0,0 +1,110 @@
+package radio
+
+import (
+	"sync"
+	"time"
+)
+
+// PacketGap wraps a radio to enforce a minimum gap between the end of
+// one transmission and the start of the next, for remote devices that
+// drop back-to-back packets. Send and SendAndReceive wait as needed.
+// The delays are recorded in the wrapper's statistics and, if there
+// is a HardwareAccessor in the chain, in the Gaps field of its Stats.
+type PacketGap struct {
+	Interface
+
+	mu       sync.Mutex
+	gap      time.Duration
+	lastSend time.Time
+	delayed  int
+	delay    time.Duration
+	clocked
+}
+
+// NewPacketGap returns a PacketGap wrapper for r with the given minimum gap.
+func NewPacketGap(r Interface, gap time.Duration) *PacketGap {
+	return &PacketGap{Interface: r, gap: gap}
+}
+
+// WithPacketGap returns middleware that enforces a minimum inter-packet gap.
+func WithPacketGap(gap time.Duration) Middleware {
+	return func(r Interface) Interface { return NewPacketGap(r, gap) }
+}
+
+// Unwrap returns the radio wrapped by p.
+func (p *PacketGap) Unwrap() Interface {
+	return p.Interface
+}
+
+// Gap returns the minimum inter-packet gap.
+func (p *PacketGap) Gap() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.gap
+}
+
+// SetGap sets the minimum inter-packet gap; zero disables it.
+func (p *PacketGap) SetGap(gap time.Duration) {
+	p.mu.Lock()
+	p.gap = gap
+	p.mu.Unlock()
+}
+
+// Delayed returns the number of transmissions that were delayed
+// and the total time spent waiting to enforce the gap.
+func (p *PacketGap) Delayed() (int, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.delayed, p.delay
+}
+
+// SetClock sets the Clock used to measure and wait out the gap,
+// typically to a FakeClock in tests. It must be called before any
+// packets are sent through p; a nil Clock means SystemClock.
+func (p *PacketGap) SetClock(clock Clock) {
+	p.clock = clock
+}
+
+// wait sleeps until the gap since the last transmission has elapsed.
+func (p *PacketGap) wait() {
+	p.mu.Lock()
+	d := time.Duration(0)
+	if !p.lastSend.IsZero() {
+		d = p.until(p.lastSend.Add(p.gap))
+	}
+	if d > 0 {
+		p.delayed++
+		p.delay += d
+	}
+	p.mu.Unlock()
+	if d <= 0 {
+		return
+	}
+	p.sleep(d)
+	if a, ok := Find(p.Interface, isHardwareAccessor).(HardwareAccessor); ok {
+		a.Hardware().RecordGap(d)
+	}
+}
+
+func (p *PacketGap) sent() {
+	p.mu.Lock()
+	p.lastSend = p.now()
+	p.mu.Unlock()
+}
+
+// Send waits for the minimum gap and then transmits data.
+func (p *PacketGap) Send(data []byte) {
+	p.wait()
+	p.Interface.Send(data)
+	p.sent()
+}
+
+// SendAndReceive waits for the minimum gap, transmits data,
+// and listens for a response.
+func (p *PacketGap) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	p.wait()
+	// The transmission ends before the receive begins,
+	// but the gap is measured conservatively from the end of the call.
+	defer p.sent()
+	return p.Interface.SendAndReceive(data, timeout)
+}
@@ -0,0 +1,59 @@
+package radio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPacketGapStats(t *testing.T) {
+	const ms = time.Millisecond
+	cases := []struct {
+		name      string
+		profiling bool
+		elapsed   time.Duration
+		want      time.Duration
+	}{
+		{"delayed", false, 3 * ms, 7 * ms},
+		{"delayed while profiling", true, 3 * ms, 7 * ms},
+		{"gap already elapsed", false, 20 * ms, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Open(testFlavor{}, DryRun(nil))
+			defer h.Close()
+			h.SetProfiling(c.profiling)
+			clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			p := NewPacketGap(NewSquelch(&hwRadio{hw: h}, 10), 10*ms)
+			p.SetClock(clock)
+			p.Send([]byte{1})
+			clock.Advance(c.elapsed)
+			done := make(chan struct{})
+			go func() {
+				p.Send([]byte{2})
+				close(done)
+			}()
+			if c.want != 0 {
+				for clock.Waiters() == 0 {
+					time.Sleep(time.Millisecond)
+				}
+				clock.Advance(c.want)
+			}
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Send did not return")
+			}
+			count := 0
+			if c.want != 0 {
+				count = 1
+			}
+			gaps := h.Stats().Gaps
+			if gaps.Count != count || gaps.Total != c.want {
+				t.Errorf("recorded %d gaps totaling %v, want %d totaling %v", gaps.Count, gaps.Total, count, c.want)
+			}
+			if n, d := p.Delayed(); n != count || d != c.want {
+				t.Errorf("Delayed() = %d, %v, want %d, %v", n, d, count, c.want)
+			}
+		})
+	}
+}
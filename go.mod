@@ -5,5 +5,5 @@ go 1.13
 require (
 	github.com/ecc1/gpio v0.0.0-20200212231225-d40e43fcf8f5
 	github.com/ecc1/spi v0.0.0-20200422200600-12b68ae2e8ca
-	golang.org/x/sys v0.5.0 // indirect
+	golang.org/x/sys v0.5.0
 )
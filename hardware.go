@@ -1,12 +1,12 @@
 package radio
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"syscall"
 	"time"
-
-	"github.com/ecc1/gpio"
-	"github.com/ecc1/spi"
 )
 
 // HardwareFlavor is the interface satisfied by a particular SPI device.
@@ -22,19 +22,53 @@ type HardwareFlavor interface {
 	WriteBurstAddress(byte) byte
 }
 
+// spiDevice is the subset of spi.Device operations used by Hardware.
+type spiDevice interface {
+	Transfer(snd, rcv []byte) error
+	SetMaxSpeed(int) error
+	Close() error
+}
+
+// outputPin is the subset of gpio.OutputPin operations used by Hardware.
+type outputPin interface {
+	Write(bool) error
+}
+
 // Hardware represents an SPI radio device.
 type Hardware struct {
-	device    *spi.Device
+	device    spiDevice
 	flavor    HardwareFlavor
+	settings  Settings
 	err       error
-	interrupt gpio.InterruptPin
+	interrupt *interruptPin
+	reset     outputPin
+	antenna   *antennaPins
 	snd       []byte
 	rcv       []byte
+
+	timeouts   Timeouts
+	busyPoll   time.Duration
+	lockThread bool
+	profiling  bool
+	stats      Stats
+	history    history
+
+	pollInterval time.Duration
+	recoverFIFO  func()
+	observeFIFO  func(FIFOError)
+	verifyWrites bool
+	maxTransfer  int
+	alignment    int
+	burst        []byte
+	ctx          context.Context
+	canceled     int32
+	closed       bool
+	clocked
 }
 
 // Device returns the radio's SPI device pathname.
 func (h *Hardware) Device() string {
-	return h.flavor.SPIDevice()
+	return h.settings.SPIDevice
 }
 
 // Error returns the error state of the radio device.
@@ -48,42 +82,111 @@ func (h *Hardware) SetError(err error) {
 }
 
 // AwaitInterrupt waits with the given timeout for a receive interrupt.
+// A non-positive timeout means the device's default interrupt timeout.
 func (h *Hardware) AwaitInterrupt(timeout time.Duration) {
-	h.err = h.interrupt.Wait(timeout)
+	if timeout <= 0 {
+		timeout = h.timeouts.Interrupt
+	}
+	h.err = h.waitInterrupt(timeout)
+	if _, ok := h.err.(InterruptTimeoutError); ok && (h.recoverFIFO != nil || h.observeFIFO != nil) {
+		err := h.err
+		h.err = nil
+		_ = h.CheckFIFO()
+		if h.err == nil {
+			h.err = err
+		}
+	}
 }
 
 // ReadInterrupt returns the state of the receive interrupt.
 func (h *Hardware) ReadInterrupt() bool {
+	if h.interrupt == nil {
+		return h.readInterruptStatus()
+	}
 	b, err := h.interrupt.Read()
 	h.err = err
 	return b
 }
 
+// Option configures a Hardware device when it is opened.
+type Option func(*Hardware)
+
 // Open opens the SPI radio module described by the given flavor.
-func Open(flavor HardwareFlavor) *Hardware {
-	h := &Hardware{flavor: flavor}
-	h.device, h.err = spi.Open(flavor.SPIDevice(), flavor.Speed(), flavor.CustomCS())
+func Open(flavor HardwareFlavor, options ...Option) *Hardware {
+	h := &Hardware{timeouts: DefaultTimeouts, pollInterval: DefaultPollInterval}
+	h.SetHistorySize(DefaultHistorySize)
+	h.flavor, h.settings = resolveSettings(flavor)
+	for _, opt := range options {
+		opt(h)
+	}
 	if h.Error() != nil {
-		return h
+		return h.abort()
 	}
-	h.err = h.device.SetMaxSpeed(flavor.Speed())
+	s := h.settings
+	if h.device == nil {
+		h.device, h.err = openSPI(s.SPIDevice, s.Speed, s.CustomCS)
+		if h.Error() != nil {
+			return h
+		}
+	}
+	h.err = h.device.SetMaxSpeed(s.Speed)
 	if h.Error() != nil {
-		h.Close()
-		return h
+		return h.abort()
+	}
+	if !h.isDryRun() && s.InterruptPin >= 0 {
+		h.interrupt, h.err = openInterrupt(s.InterruptPin)
+		if h.Error() != nil {
+			return h.abort()
+		}
 	}
-	h.interrupt, h.err = gpio.Interrupt(flavor.InterruptPin(), false, "rising")
+	h.openAntenna()
 	if h.Error() != nil {
-		h.Close()
-		return h
+		return h.abort()
 	}
-	h.snd = make([]byte, 2)
-	h.rcv = make([]byte, 2)
+	h.snd = h.newBuffer(2)
+	h.rcv = h.newBuffer(2)
+	return h
+}
+
+// abort closes a device that failed to open, keeping the error that caused it.
+func (h *Hardware) abort() *Hardware {
+	err := h.err
+	h.Close()
+	h.err = err
 	return h
 }
 
-// Close closes the radio device.
+// Close closes the radio device and unexports any GPIO pins it used.
+// The antenna control lines are driven low first, so that neither
+// the transmit nor the receive path is left powered. Closing a device
+// more than once has no further effect, so it is safe after Shutdown.
 func (h *Hardware) Close() {
-	h.err = h.device.Close()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	if h.interrupt != nil {
+		_ = h.interrupt.Close()
+		h.interrupt = nil
+	}
+	if f, ok := h.flavor.(ResetFlavor); ok && h.reset != nil {
+		unexportPin(f.ResetPin())
+	}
+	h.reset = nil
+	if f, ok := h.flavor.(AntennaFlavor); ok && h.antenna != nil {
+		h.antenna.off()
+		tx, rx := f.AntennaPins()
+		for _, pin := range []int{tx, rx} {
+			if pin >= 0 {
+				unexportPin(pin)
+			}
+		}
+	}
+	h.antenna = nil
+	// Hardware that failed to open may have no device.
+	if h.device != nil {
+		h.err = h.device.Close()
+	}
 }
 
 // ReadRegister reads the given address on the radio device.
@@ -92,7 +195,8 @@ func (h *Hardware) ReadRegister(addr byte) byte {
 		return 0
 	}
 	h.snd[0] = h.flavor.ReadSingleAddress(addr)
-	h.err = h.device.Transfer(h.snd, h.rcv)
+	err := h.transfer(h.snd, h.rcv)
+	h.complete(ReadOp, addr, h.rcv[1:2], err)
 	return h.rcv[1]
 }
 
@@ -101,25 +205,65 @@ func (h *Hardware) ReadBurst(addr byte, n int) []byte {
 	if h.Error() != nil {
 		return nil
 	}
-	buf := make([]byte, n+1)
+	if h.chunkSize(n) < n {
+		return h.readChunks(addr, n)
+	}
+	buf := h.buffer(n + 1)
 	buf[0] = h.flavor.ReadBurstAddress(addr)
-	h.err = h.device.Transfer(buf, buf)
+	err := h.transfer(buf, buf)
+	h.complete(ReadBurstOp, addr, buf[1:], err)
+	if h.alignment > 1 {
+		// The aligned buffer is reused by the next burst.
+		return append([]byte(nil), buf[1:]...)
+	}
 	return buf[1:]
 }
 
 // WriteRegister writes the given value to the given address on the radio device.
 func (h *Hardware) WriteRegister(addr byte, value byte) {
+	if h.startsTransmit(addr, value) {
+		h.AntennaTransmit()
+	}
 	h.snd[0] = h.flavor.WriteSingleAddress(addr)
 	h.snd[1] = value
-	h.err = h.device.Transfer(h.snd, h.rcv)
+	err := h.transfer(h.snd, h.rcv)
+	h.complete(WriteOp, addr, h.snd[1:2], err)
+	h.verify(addr, []byte{value})
 }
 
 // WriteBurst writes data in burst mode to the given address on the radio device.
 func (h *Hardware) WriteBurst(addr byte, data []byte) {
-	buf := make([]byte, len(data)+1)
+	if h.chunkSize(len(data)) < len(data) {
+		h.writeChunks(addr, data)
+		h.verify(addr, data)
+		return
+	}
+	buf := h.buffer(len(data) + 1)
 	buf[0] = h.flavor.WriteBurstAddress(addr)
 	copy(buf[1:], data)
-	h.err = h.device.Transfer(buf, buf)
+	err := h.transfer(buf, buf)
+	h.complete(WriteBurstOp, addr, data, err)
+	h.verify(addr, data)
+}
+
+func (h *Hardware) transfer(snd, rcv []byte) error {
+	if !h.profiling {
+		return h.retryTransfer(snd, rcv)
+	}
+	start := time.Now()
+	err := h.retryTransfer(snd, rcv)
+	h.stats.Transfers.Add(time.Since(start))
+	return err
+}
+
+// retryTransfer restarts transfers interrupted by a signal.
+func (h *Hardware) retryTransfer(snd, rcv []byte) error {
+	for {
+		err := h.device.Transfer(snd, rcv)
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
 }
 
 // WriteEach writes each address-value pairs in data to the radio device.
@@ -133,11 +277,6 @@ func (h *Hardware) WriteEach(data []byte) {
 	}
 }
 
-// SPIDevice returns the radio's SPI device.
-func (h *Hardware) SPIDevice() *spi.Device {
-	return h.device
-}
-
 // HardwareVersionError indicates a hardware version mismatch.
 type HardwareVersionError struct {
 	Actual   uint16
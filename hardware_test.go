@@ -0,0 +1,100 @@
+package radio
+
+import (
+	"testing"
+	"time"
+)
+
+// hwRadio is a minimal driver around a Hardware device.
+// Reset, if not nil, is called in place of resetting the chip.
+type hwRadio struct {
+	hw    *Hardware
+	reset func(h *Hardware)
+	freq  uint32
+}
+
+func (r *hwRadio) Hardware() *Hardware { return r.hw }
+func (r *hwRadio) Close()              { r.hw.Close() }
+func (r *hwRadio) Error() error        { return r.hw.Error() }
+func (r *hwRadio) SetError(err error)  { r.hw.SetError(err) }
+func (r *hwRadio) Device() string      { return r.hw.Device() }
+
+func (r *hwRadio) Init(frequency uint32)         { r.freq = frequency }
+func (r *hwRadio) Frequency() uint32             { return r.freq }
+func (r *hwRadio) SetFrequency(frequency uint32) { r.freq = frequency }
+func (r *hwRadio) Send([]byte)                   {}
+func (r *hwRadio) State() string                 { return "Idle" }
+func (r *hwRadio) Name() string                  { return "test" }
+
+func (r *hwRadio) Reset() {
+	if r.reset != nil {
+		r.reset(r.hw)
+	}
+}
+
+func (r *hwRadio) Receive(timeout time.Duration) ([]byte, int) {
+	r.hw.AwaitInterrupt(timeout)
+	return nil, 0
+}
+
+func (r *hwRadio) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	r.Send(data)
+	return r.Receive(timeout)
+}
+
+// closeCounter is a dry-run device that counts calls to Close.
+type closeCounter struct {
+	dryRunDevice
+	closes int
+}
+
+func (d *closeCounter) Close() error {
+	d.closes++
+	return nil
+}
+
+// closeFlavor has antenna lines but no interrupt line,
+// so it can be opened on a device that is not a dry run.
+type closeFlavor struct{ antennaFlavor }
+
+func (closeFlavor) InterruptPin() int { return -1 }
+
+func TestCloseTwice(t *testing.T) {
+	cases := []struct {
+		name  string
+		close func(h *Hardware)
+	}{
+		{"Close", func(h *Hardware) { h.Close() }},
+		{"Shutdown", func(h *Hardware) { Shutdown(&hwRadio{hw: h}) }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dev := &closeCounter{}
+			h := Open(closeFlavor{}, func(h *Hardware) { h.device = dev })
+			if h.Error() != nil {
+				t.Fatal(h.Error())
+			}
+			tx, rx := &fakePin{}, &fakePin{}
+			h.antenna = &antennaPins{tx: tx, rx: rx}
+			c.close(h)
+			// A second Close must not touch the pins or the device again.
+			tx.value, rx.value = true, true
+			h.Close()
+			if dev.closes != 1 {
+				t.Errorf("device closed %d times, want 1", dev.closes)
+			}
+			if !tx.value || !rx.value {
+				t.Errorf("second Close drove the antenna lines")
+			}
+		})
+	}
+}
+
+func TestStubSPIDevice(t *testing.T) {
+	// This must compile on every platform, including those without SPI.
+	h := Open(testFlavor{}, Stub())
+	defer h.Close()
+	if h.SPIDevice() != nil {
+		t.Error("stub device has an SPI device")
+	}
+}
@@ -0,0 +1,74 @@
+package radio_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+// simPair returns two simulated radios on the same medium and frequency.
+func simPair(t *testing.T) (*sim.Medium, *sim.Radio, *sim.Radio) {
+	t.Helper()
+	m := sim.NewMedium()
+	a, b := m.NewRadio("a"), m.NewRadio("b")
+	a.Init(916500000)
+	b.Init(916500000)
+	return m, a, b
+}
+
+// rateRadio is a simulated radio with an adjustable data rate.
+type rateRadio struct {
+	*sim.Radio
+	rate uint32
+}
+
+func (r *rateRadio) DataRate() uint32 { return r.rate }
+
+func (r *rateRadio) SetDataRate(bps uint32) { r.rate = bps }
+
+func (r *rateRadio) Unwrap() radio.Interface { return r.Radio }
+
+// recorder is a simulated radio that records the packets it sends
+// and the frequencies it sends them on.
+// If gate is not nil, each Send first waits to receive from it;
+// if fail is not nil, Send sets the error state to it instead of sending.
+type recorder struct {
+	*sim.Radio
+	gate chan struct{}
+	fail error
+
+	mu    sync.Mutex
+	sent  [][]byte
+	freqs []uint32
+}
+
+func (r *recorder) Send(data []byte) {
+	if r.gate != nil {
+		<-r.gate
+	}
+	if r.fail != nil {
+		r.SetError(r.fail)
+		return
+	}
+	r.mu.Lock()
+	r.sent = append(r.sent, append([]byte(nil), data...))
+	r.freqs = append(r.freqs, r.Frequency())
+	r.mu.Unlock()
+	r.Radio.Send(data)
+}
+
+func (r *recorder) packets() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]byte(nil), r.sent...)
+}
+
+func (r *recorder) frequencies() []uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]uint32(nil), r.freqs...)
+}
+
+func (r *recorder) Unwrap() radio.Interface { return r.Radio }
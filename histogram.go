@@ -0,0 +1,121 @@
+package radio
+
+import (
+	"math/bits"
+	"strconv"
+	"time"
+)
+
+// Histogram buckets durations on a log-linear scale, in the style of
+// HDR histograms: each power-of-two range of nanoseconds is divided
+// into 8 equal sub-buckets, so values are recorded with a relative
+// error of at most 12.5%. Durations of 2^40 ns (about 18 minutes)
+// or more share the last bucket.
+type Histogram struct {
+	counts [histogramBuckets]uint64
+	total  uint64
+}
+
+const (
+	histogramSubBits = 3
+	histogramSub     = 1 << histogramSubBits
+	histogramMaxExp  = 40
+	histogramBuckets = (histogramMaxExp - histogramSubBits + 1) * histogramSub
+)
+
+func histogramIndex(d time.Duration) int {
+	if d < histogramSub {
+		if d < 0 {
+			return 0
+		}
+		return int(d)
+	}
+	v := uint64(d)
+	e := bits.Len64(v) - 1
+	if e >= histogramMaxExp {
+		return histogramBuckets - 1
+	}
+	sub := int(v>>uint(e-histogramSubBits)) & (histogramSub - 1)
+	return (e-histogramSubBits+1)*histogramSub + sub
+}
+
+// bucketRange returns the smallest value in bucket i and the bucket's width.
+func bucketRange(i int) (time.Duration, time.Duration) {
+	if i < histogramSub {
+		return time.Duration(i), 1
+	}
+	e := uint(i/histogramSub + histogramSubBits - 1)
+	sub := i % histogramSub
+	width := time.Duration(1) << (e - histogramSubBits)
+	return time.Duration(histogramSub+sub) * width, width
+}
+
+// Record adds a duration to the histogram.
+func (h *Histogram) Record(d time.Duration) {
+	h.counts[histogramIndex(d)]++
+	h.total++
+}
+
+// Count returns the number of recorded durations.
+func (h *Histogram) Count() uint64 {
+	return h.total
+}
+
+// Quantile returns an estimate of the q-th quantile (0 <= q <= 1)
+// of the recorded durations, or 0 if there are none.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(q*float64(h.total-1)) + 1
+	var n uint64
+	for i, c := range h.counts {
+		n += c
+		if n >= rank {
+			low, width := bucketRange(i)
+			return low + width/2
+		}
+	}
+	return 0
+}
+
+// HistogramBucket is a non-empty bucket of a Histogram,
+// holding Count durations in the range [Low, Low+Width).
+type HistogramBucket struct {
+	Low   time.Duration
+	Width time.Duration
+	Count uint64
+}
+
+// Buckets returns the non-empty buckets in increasing order,
+// for export to a metrics system.
+func (h *Histogram) Buckets() []HistogramBucket {
+	var b []HistogramBucket
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		low, width := bucketRange(i)
+		b = append(b, HistogramBucket{Low: low, Width: width, Count: c})
+	}
+	return b
+}
+
+// MarshalJSON encodes the histogram as its non-empty buckets.
+// It is written by hand so that this package does not need encoding/json.
+func (h Histogram) MarshalJSON() ([]byte, error) {
+	buf := []byte{'['}
+	for i, b := range h.Buckets() {
+		if i != 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `{"Low":`...)
+		buf = strconv.AppendInt(buf, int64(b.Low), 10)
+		buf = append(buf, `,"Width":`...)
+		buf = strconv.AppendInt(buf, int64(b.Width), 10)
+		buf = append(buf, `,"Count":`...)
+		buf = strconv.AppendUint(buf, b.Count, 10)
+		buf = append(buf, '}')
+	}
+	return append(buf, ']'), nil
+}
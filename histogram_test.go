@@ -0,0 +1,108 @@
+package radio
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	cases := []time.Duration{
+		0, 1, 7, 8, 9, 15, 16, 17, 100, 999,
+		time.Microsecond, 1234567, time.Millisecond, time.Second,
+		17 * time.Minute, 1<<histogramMaxExp - 1,
+	}
+	for _, d := range cases {
+		i := histogramIndex(d)
+		low, width := bucketRange(i)
+		if d < low || d >= low+width {
+			t.Errorf("%d: bucket %d holds [%d, %d)", d, i, low, low+width)
+		}
+		if d >= histogramSub && float64(width) > float64(d)/histogramSub {
+			t.Errorf("%d: bucket width %d exceeds 1/%d of the value", d, width, histogramSub)
+		}
+	}
+	// Out-of-range values are clamped to the first and last buckets.
+	for _, c := range []struct {
+		d time.Duration
+		i int
+	}{
+		{-time.Second, 0},
+		{1 << histogramMaxExp, histogramBuckets - 1},
+		{time.Duration(1<<63 - 1), histogramBuckets - 1},
+	} {
+		if i := histogramIndex(c.d); i != c.i {
+			t.Errorf("histogramIndex(%d) = %d, want %d", c.d, i, c.i)
+		}
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	uniform := func(n int) []time.Duration {
+		var ds []time.Duration
+		for i := 1; i <= n; i++ {
+			ds = append(ds, time.Duration(i)*time.Millisecond)
+		}
+		return ds
+	}
+	cases := []struct {
+		name   string
+		record []time.Duration
+		q      float64
+		want   time.Duration
+	}{
+		{"empty", nil, 0.5, 0},
+		{"single", []time.Duration{time.Millisecond}, 0.99, time.Millisecond},
+		{"min", uniform(100), 0, time.Millisecond},
+		{"median", uniform(100), 0.5, 50 * time.Millisecond},
+		{"p99", uniform(100), 0.99, 99 * time.Millisecond},
+		{"max", uniform(100), 1, 100 * time.Millisecond},
+		{"outlier", append(uniform(99), time.Minute), 1, time.Minute},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var h Histogram
+			for _, d := range c.record {
+				h.Record(d)
+			}
+			if h.Count() != uint64(len(c.record)) {
+				t.Errorf("Count() = %d, want %d", h.Count(), len(c.record))
+			}
+			got := h.Quantile(c.q)
+			diff := got - c.want
+			if diff < 0 {
+				diff = -diff
+			}
+			if float64(diff) > float64(c.want)/histogramSub {
+				t.Errorf("Quantile(%v) = %v, want %v within 1/%d", c.q, got, c.want, histogramSub)
+			}
+		})
+	}
+}
+
+func TestHistogramJSON(t *testing.T) {
+	var h Histogram
+	for _, d := range []time.Duration{3, 3, 100, time.Millisecond} {
+		h.Record(d)
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []HistogramBucket
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("%s: %v", data, err)
+	}
+	want := h.Buckets()
+	if len(got) != 3 || len(got) != len(want) {
+		t.Fatalf("decoded %+v, want %+v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if want[0] != (HistogramBucket{Low: 3, Width: 1, Count: 2}) {
+		t.Errorf("first bucket = %+v", want[0])
+	}
+}
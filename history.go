@@ -0,0 +1,179 @@
+package radio
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// RegisterOpKind identifies the kind of a register operation.
+type RegisterOpKind byte
+
+// Kinds of register operations.
+const (
+	ReadOp RegisterOpKind = iota
+	ReadBurstOp
+	WriteOp
+	WriteBurstOp
+)
+
+func (k RegisterOpKind) String() string {
+	switch k {
+	case ReadOp:
+		return "ReadRegister"
+	case ReadBurstOp:
+		return "ReadBurst"
+	case WriteOp:
+		return "WriteRegister"
+	case WriteBurstOp:
+		return "WriteBurst"
+	default:
+		return fmt.Sprintf("RegisterOpKind(%d)", k)
+	}
+}
+
+// maxHistoryData is the number of data bytes kept for each burst operation.
+const maxHistoryData = 16
+
+// RegisterOp records a register operation performed by a Hardware device.
+// Data holds the value read or written, truncated for long bursts,
+// and Length is the full number of bytes transferred.
+type RegisterOp struct {
+	Time   time.Time
+	Kind   RegisterOpKind
+	Addr   byte
+	Data   []byte
+	Length int
+	Err    error
+}
+
+func (op RegisterOp) String() string {
+	s := fmt.Sprintf("%s %s %02X % X", op.Time.Format("15:04:05.000000"), op.Kind, op.Addr, op.Data)
+	if op.Length > len(op.Data) {
+		s += fmt.Sprintf(" ... (%d bytes)", op.Length)
+	}
+	if op.Err != nil {
+		s += ": " + op.Err.Error()
+	}
+	return s
+}
+
+// history is a ring buffer of the most recent register operations.
+// The data of each operation is copied into storage allocated with the
+// buffer, so recording an operation does not allocate.
+type history struct {
+	ops  []RegisterOp
+	data []byte
+	next int
+	full bool
+}
+
+func newHistory(n int) history {
+	return history{ops: make([]RegisterOp, n), data: make([]byte, n*maxHistoryData)}
+}
+
+func (r *history) add(kind RegisterOpKind, addr byte, data []byte, err error) {
+	if len(r.ops) == 0 {
+		return
+	}
+	n := len(data)
+	if n > maxHistoryData {
+		n = maxHistoryData
+	}
+	buf := r.data[r.next*maxHistoryData : r.next*maxHistoryData+n]
+	copy(buf, data)
+	r.ops[r.next] = RegisterOp{
+		Time:   time.Now(),
+		Kind:   kind,
+		Addr:   addr,
+		Data:   buf,
+		Length: len(data),
+		Err:    err,
+	}
+	r.next++
+	if r.next == len(r.ops) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// list returns copies of the recorded operations, oldest first.
+func (r *history) list() []RegisterOp {
+	var ops []RegisterOp
+	if r.full {
+		ops = append(ops, r.ops[r.next:]...)
+	}
+	ops = append(ops, r.ops[:r.next]...)
+	for i := range ops {
+		ops[i].Data = append([]byte(nil), ops[i].Data...)
+	}
+	return ops
+}
+
+// DefaultHistorySize is the number of register operations
+// recorded by a newly opened Hardware device.
+const DefaultHistorySize = 32
+
+// SetHistorySize sets the number of recent register operations that are
+// recorded and attached to transfer errors. Zero disables recording.
+// Recording does not allocate, but reads the time for each operation.
+func (h *Hardware) SetHistorySize(n int) {
+	h.history = newHistory(n)
+}
+
+// History returns the most recent register operations, oldest first.
+func (h *Hardware) History() []RegisterOp {
+	return h.history.list()
+}
+
+// complete records a register operation and updates the error state.
+func (h *Hardware) complete(kind RegisterOpKind, addr byte, data []byte, err error) {
+	h.history.add(kind, addr, data, err)
+	if err != nil {
+		err = &OperationError{Op: kind, Addr: addr, Err: err, History: h.History()}
+	}
+	h.err = err
+}
+
+// OperationError records a failed register operation
+// along with the operations that preceded it.
+type OperationError struct {
+	Op      RegisterOpKind
+	Addr    byte
+	Err     error
+	History []RegisterOp
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("%s %02X: %v", e.Op, e.Addr, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Detail formats the error together with the given chip state
+// and the register history leading up to it.
+func (e *OperationError) Detail(state string) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s (state %s)\n", e.Error(), state)
+	for _, op := range e.History {
+		fmt.Fprintf(&buf, "  %s\n", op)
+	}
+	return buf.String()
+}
+
+// ErrorDetail describes the error state of r, including the
+// chip state and, for register operation failures, the register history.
+// It returns the empty string if r has no error.
+func ErrorDetail(r Interface) string {
+	err := r.Error()
+	if err == nil {
+		return ""
+	}
+	if e, ok := err.(*OperationError); ok {
+		return e.Detail(r.State())
+	}
+	return fmt.Sprintf("%v (state %s)\n", err, r.State())
+}
@@ -0,0 +1,79 @@
+package radio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	cases := []struct {
+		name  string
+		size  int
+		ops   int
+		addrs []byte
+	}{
+		{"disabled", 0, 3, nil},
+		{"partial", 4, 2, []byte{0, 1}},
+		{"full", 4, 4, []byte{0, 1, 2, 3}},
+		{"wrapped", 4, 6, []byte{2, 3, 4, 5}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := newHistory(c.size)
+			for i := 0; i < c.ops; i++ {
+				r.add(WriteOp, byte(i), []byte{byte(i)}, nil)
+			}
+			ops := r.list()
+			if len(ops) != len(c.addrs) {
+				t.Fatalf("recorded %d operations, want %d", len(ops), len(c.addrs))
+			}
+			for i, op := range ops {
+				if op.Addr != c.addrs[i] || !bytes.Equal(op.Data, []byte{c.addrs[i]}) {
+					t.Errorf("operation %d = %v, want address and data %02X", i, op, c.addrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryData(t *testing.T) {
+	long := make([]byte, 2*maxHistoryData)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	cases := []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{"empty", nil, []byte{}},
+		{"short", []byte{1, 2, 3}, []byte{1, 2, 3}},
+		{"truncated", long, long[:maxHistoryData]},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := newHistory(1)
+			r.add(WriteBurstOp, 0, c.data, nil)
+			op := r.list()[0]
+			if !bytes.Equal(op.Data, c.want) || op.Length != len(c.data) {
+				t.Errorf("recorded %X (%d bytes), want %X (%d bytes)", op.Data, op.Length, c.want, len(c.data))
+			}
+			// Later operations must not change operations already listed.
+			r.add(WriteBurstOp, 0, bytes.Repeat([]byte{0xFF}, maxHistoryData), nil)
+			if !bytes.Equal(op.Data, c.want) {
+				t.Errorf("listed data changed to %X", op.Data)
+			}
+		})
+	}
+}
+
+func TestHistoryAllocs(t *testing.T) {
+	r := newHistory(DefaultHistorySize)
+	data := make([]byte, 64)
+	n := testing.AllocsPerRun(100, func() {
+		r.add(ReadBurstOp, 0, data, nil)
+	})
+	if n != 0 {
+		t.Errorf("recording an operation made %v allocations", n)
+	}
+}
@@ -0,0 +1,101 @@
+package radio
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// InterruptTimeoutError indicates that a wait for an interrupt timed out.
+//
+// Interrupt waits no longer go through the gpio package, so they report
+// this error instead of gpio.TimeoutError, which cannot be constructed
+// or wrapped outside that package. This is an incompatible change for
+// drivers that test for gpio.TimeoutError: such checks still compile
+// but no longer match. Use IsTimeout instead, which also matches
+// receive timeouts. The message has the same form as before.
+type InterruptTimeoutError struct {
+	Pin     int
+	Timeout time.Duration
+}
+
+func (e InterruptTimeoutError) Error() string {
+	return fmt.Sprintf("gpio%d.Wait timeout after %v", e.Pin, e.Timeout)
+}
+
+// ErrWaitCanceled indicates that an interrupt wait was canceled by CancelWaits.
+var ErrWaitCanceled = errors.New("interrupt wait canceled")
+
+// CancelWaits makes interrupt and polling waits in progress, and any
+// that follow, return ErrWaitCanceled, so that goroutines blocked in
+// Receive or Init can be stopped before the device is closed.
+func (h *Hardware) CancelWaits() {
+	atomic.StoreInt32(&h.canceled, 1)
+	if h.interrupt != nil {
+		_ = h.interrupt.Cancel()
+	}
+}
+
+// resumeWaits undoes CancelWaits, so that the device can wait
+// for the chip again, as it must while being reset.
+func (h *Hardware) resumeWaits() {
+	if h.interrupt != nil {
+		_ = h.interrupt.Resume()
+	}
+	atomic.StoreInt32(&h.canceled, 0)
+}
+
+func (h *Hardware) waitsCanceled() bool {
+	return atomic.LoadInt32(&h.canceled) != 0
+}
+
+// SetBusyPoll sets the portion at the end of each interrupt wait
+// that is spent busy-polling the pin instead of sleeping in the kernel.
+// This trades CPU time for more precise wakeups; zero disables it.
+func (h *Hardware) SetBusyPoll(threshold time.Duration) {
+	h.busyPoll = threshold
+}
+
+// SetLockThread controls whether interrupt waits lock the calling
+// goroutine to its OS thread, so it is not rescheduled onto a
+// different thread while waiting for a wakeup.
+func (h *Hardware) SetLockThread(lock bool) {
+	h.lockThread = lock
+}
+
+func (h *Hardware) waitInterrupt(timeout time.Duration) error {
+	if h.waitsCanceled() {
+		return ErrWaitCanceled
+	}
+	if h.isStub() {
+		// Nothing will ever arrive, but don't let receive loops spin.
+		// Polling waits on the device's Clock and stops early if canceled.
+		return h.pollInterrupt(timeout)
+	}
+	if h.isDryRun() {
+		return InterruptTimeoutError{Pin: h.settings.InterruptPin, Timeout: timeout}
+	}
+	if h.lockThread {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+	}
+	wait := h.pollInterrupt
+	if h.interrupt != nil {
+		wait = func(timeout time.Duration) error {
+			return h.interrupt.wait(timeout, h.busyPoll)
+		}
+	}
+	if !h.profiling {
+		return wait(timeout)
+	}
+	start := time.Now()
+	err := wait(timeout)
+	elapsed := time.Since(start)
+	h.stats.Interrupts.Add(elapsed)
+	if _, ok := err.(InterruptTimeoutError); ok {
+		h.stats.Wakeups.Add(elapsed - timeout)
+	}
+	return err
+}
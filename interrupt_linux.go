@@ -0,0 +1,135 @@
+//go:build linux
+// +build linux
+
+package radio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/ecc1/gpio"
+	"golang.org/x/sys/unix"
+)
+
+// interruptPin waits for edges on a GPIO pin using ppoll(2),
+// which accepts nanosecond timeouts, and keeps the sysfs value file
+// open between waits to avoid the cost of reopening it each time.
+// The cancel eventfd is polled alongside it so that waits in other
+// goroutines can be interrupted.
+type interruptPin struct {
+	pin    int
+	fd     int
+	cancel int
+	buf    []byte
+}
+
+func openInterrupt(pin int) (*interruptPin, error) {
+	// Let the gpio package export the pin and configure the edge.
+	_, err := gpio.Interrupt(pin, false, "rising")
+	if err != nil {
+		return nil, err
+	}
+	value := fmt.Sprintf("/sys/class/gpio/gpio%d/value", pin)
+	fd, err := unix.Open(value, unix.O_RDONLY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	cancel, err := unix.Eventfd(0, unix.EFD_NONBLOCK)
+	if err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+	// This must be long enough to read the entire value file (0 or 1 and newline).
+	return &interruptPin{pin: pin, fd: fd, cancel: cancel, buf: make([]byte, 4)}, nil
+}
+
+// Read returns the current state of the pin.
+// Reading the value file also clears any pending edge notification.
+func (p *interruptPin) Read() (bool, error) {
+	for {
+		_, err := unix.Pread(p.fd, p.buf, 0)
+		if err != unix.EINTR {
+			return p.buf[0] == '1', err
+		}
+	}
+}
+
+// Wait waits with the given timeout for the pin to become active.
+func (p *interruptPin) Wait(timeout time.Duration) error {
+	return p.wait(timeout, 0)
+}
+
+// wait blocks in ppoll until spin before the deadline,
+// then busy-polls the pin value for the remaining time.
+func (p *interruptPin) wait(timeout time.Duration, spin time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	active, err := p.Read()
+	if err != nil || active {
+		return err
+	}
+	fds := []unix.PollFd{
+		{Fd: int32(p.fd), Events: unix.POLLPRI | unix.POLLERR},
+		{Fd: int32(p.cancel), Events: unix.POLLIN},
+	}
+	for left := time.Until(deadline); left > spin; left = time.Until(deadline) {
+		ts := unix.NsecToTimespec(int64(left - spin))
+		n, err := unix.Ppoll(fds, &ts, nil)
+		if err == unix.EINTR {
+			// Interrupted by a signal: wait again for the remaining time.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if fds[1].Revents != 0 {
+			return ErrWaitCanceled
+		}
+		if n != 0 {
+			_, err = p.Read()
+			return err
+		}
+		break
+	}
+	for time.Now().Before(deadline) {
+		active, err = p.Read()
+		if err != nil || active {
+			return err
+		}
+	}
+	return InterruptTimeoutError{Pin: p.pin, Timeout: timeout}
+}
+
+// Cancel makes current and future waits return ErrWaitCanceled.
+func (p *interruptPin) Cancel() error {
+	// Any nonzero counter value will do, regardless of byte order.
+	_, err := unix.Write(p.cancel, []byte{1, 1, 1, 1, 1, 1, 1, 1})
+	return err
+}
+
+// Resume clears a previous Cancel, so that waits block again.
+func (p *interruptPin) Resume() error {
+	var buf [8]byte
+	_, err := unix.Read(p.cancel, buf[:])
+	if err == unix.EAGAIN {
+		// Not canceled.
+		return nil
+	}
+	return err
+}
+
+// Close releases the pin's value file and unexports the pin.
+func (p *interruptPin) Close() error {
+	_ = unix.Close(p.cancel)
+	err := unix.Close(p.fd)
+	unexportPin(p.pin)
+	return err
+}
+
+// unexportPin removes the sysfs directory for a GPIO pin
+// exported on behalf of the device, so it is not left behind
+// when the process exits.
+func unexportPin(pin int) {
+	_ = ioutil.WriteFile("/sys/class/gpio/unexport", []byte(strconv.Itoa(pin)), 0)
+}
@@ -0,0 +1,38 @@
+//go:build !linux
+// +build !linux
+
+package radio
+
+import (
+	"time"
+)
+
+// interruptPin is not supported on this platform;
+// devices are opened in dry-run mode and never use one.
+type interruptPin struct{}
+
+func openInterrupt(pin int) (*interruptPin, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+func (p *interruptPin) Read() (bool, error) {
+	return false, ErrUnsupportedPlatform
+}
+
+func (p *interruptPin) wait(timeout time.Duration, spin time.Duration) error {
+	return ErrUnsupportedPlatform
+}
+
+func (p *interruptPin) Cancel() error {
+	return ErrUnsupportedPlatform
+}
+
+func (p *interruptPin) Resume() error {
+	return ErrUnsupportedPlatform
+}
+
+func (p *interruptPin) Close() error {
+	return nil
+}
+
+func unexportPin(pin int) {}
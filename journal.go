@@ -0,0 +1,200 @@
+package radio
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// journalSuffix is the file name suffix of journal segments.
+const journalSuffix = ".trace"
+
+// JournalOptions configures a Journal.
+type JournalOptions struct {
+	// MaxSize is the size in bytes at which a new segment is started.
+	MaxSize int64
+	// MaxAge is the age at which a new segment is started.
+	MaxAge time.Duration
+	// MaxSegments is the number of segments kept; older ones are deleted.
+	// Zero keeps all segments.
+	MaxSegments int
+	// Clock provides the time. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// DefaultJournalOptions are used for zero MaxSize and MaxAge fields of JournalOptions.
+var DefaultJournalOptions = JournalOptions{
+	MaxSize: 16 << 20,
+	MaxAge:  24 * time.Hour,
+}
+
+// Journal appends received packets to trace files in a directory,
+// starting a new segment when the current one grows too large or old.
+// Segments are named by their creation time, so they sort in order.
+type Journal struct {
+	dir  string
+	opts JournalOptions
+	clocked
+
+	mu      sync.Mutex
+	file    *os.File
+	writer  *TraceWriter
+	size    int64
+	created time.Time
+}
+
+// OpenJournal opens a journal in the given directory, creating it if necessary.
+func OpenJournal(dir string, opts JournalOptions) (*Journal, error) {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultJournalOptions.MaxSize
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultJournalOptions.MaxAge
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{dir: dir, opts: opts, clocked: clocked{opts.Clock}}, nil
+}
+
+// Write appends p to the journal.
+func (j *Journal) Write(p Packet) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil || j.size >= j.opts.MaxSize || j.now().Sub(j.created) >= j.opts.MaxAge {
+		err := j.rotate()
+		if err != nil {
+			return err
+		}
+	}
+	err := j.writer.Write(p)
+	if err != nil {
+		// Remove any partial record, so that later packets can be read
+		// back, or start a new segment if the file cannot be truncated.
+		if j.file.Truncate(j.size) != nil {
+			_ = j.file.Close()
+			j.file = nil
+		}
+		return err
+	}
+	j.size += int64(traceHeaderLen + len(p.Data))
+	return nil
+}
+
+// rotate closes the current segment and starts a new one.
+func (j *Journal) rotate() error {
+	if j.file != nil {
+		err := j.file.Close()
+		j.file = nil
+		if err != nil {
+			return err
+		}
+	}
+	j.created = j.now()
+	name := filepath.Join(j.dir, j.created.UTC().Format("20060102T150405.000000000")+journalSuffix)
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	j.file = f
+	j.writer = NewTraceWriter(f)
+	j.size = 0
+	return j.prune()
+}
+
+// prune removes the oldest segments beyond MaxSegments.
+func (j *Journal) prune() error {
+	if j.opts.MaxSegments <= 0 {
+		return nil
+	}
+	segments, err := journalSegments(j.dir)
+	if err != nil {
+		return err
+	}
+	for len(segments) > j.opts.MaxSegments {
+		err = os.Remove(segments[0])
+		if err != nil {
+			return err
+		}
+		segments = segments[1:]
+	}
+	return nil
+}
+
+// Sync flushes the current segment to stable storage.
+func (j *Journal) Sync() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	return j.file.Sync()
+}
+
+// Close closes the journal.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+func journalSegments(dir string) ([]string, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*"+journalSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ReadJournal calls f for each packet in the journal in the given directory,
+// oldest first, that was received at or after since.
+// A segment truncated by a crash ends at its last complete packet.
+func ReadJournal(dir string, since time.Time, f func(Packet) error) error {
+	segments, err := journalSegments(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range segments {
+		err = readSegment(name, since, f)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSegment(name string, since time.Time, f func(Packet) error) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	t := NewTraceReader(file)
+	for {
+		p, err := t.Read()
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		if p.Time.Before(since) {
+			continue
+		}
+		err = f(p)
+		if err != nil {
+			return err
+		}
+	}
+}
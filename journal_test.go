@@ -0,0 +1,186 @@
+package radio
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func journalPackets(n int) []Packet {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var ps []Packet
+	for i := 0; i < n; i++ {
+		ps = append(ps, Packet{
+			Data:      []byte{byte(i), 0xA5, 0x5A},
+			RSSI:      -40 - i,
+			Frequency: 916500000,
+			Time:      t0.Add(time.Duration(i) * time.Second),
+		})
+	}
+	return ps
+}
+
+func readAll(t *testing.T, dir string, since time.Time) []Packet {
+	t.Helper()
+	var got []Packet
+	err := ReadJournal(dir, since, func(p Packet) error {
+		got = append(got, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func samePackets(a, b []Packet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if string(a[i].Data) != string(b[i].Data) || a[i].RSSI != b[i].RSSI ||
+			a[i].Frequency != b[i].Frequency || !a[i].Time.Equal(b[i].Time) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestJournal(t *testing.T) {
+	const record = traceHeaderLen + 3
+	cases := []struct {
+		name     string
+		opts     JournalOptions
+		packets  int
+		segments int
+		kept     int
+	}{
+		{"one segment", JournalOptions{}, 5, 1, 5},
+		{"rotate by size", JournalOptions{MaxSize: 2 * record}, 5, 3, 5},
+		{"prune", JournalOptions{MaxSize: 2 * record, MaxSegments: 2}, 5, 2, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			j, err := OpenJournal(dir, c.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ps := journalPackets(c.packets)
+			for _, p := range ps {
+				// Segment names come from the clock, so keep them distinct.
+				time.Sleep(time.Millisecond)
+				if err := j.Write(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := j.Close(); err != nil {
+				t.Fatal(err)
+			}
+			segments, err := journalSegments(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(segments) != c.segments {
+				t.Errorf("%d segments, want %d", len(segments), c.segments)
+			}
+			want := ps[len(ps)-c.kept:]
+			if got := readAll(t, dir, time.Time{}); !samePackets(got, want) {
+				t.Errorf("read %v, want %v", got, want)
+			}
+			if got := readAll(t, dir, ps[len(ps)-1].Time); !samePackets(got, ps[len(ps)-1:]) {
+				t.Errorf("read %v since the last packet, want only it", got)
+			}
+		})
+	}
+}
+
+// shortWriter writes only the first n bytes of each call to f, then fails.
+type shortWriter struct {
+	f *os.File
+	n int
+}
+
+var errShort = errors.New("no space left on device")
+
+func (w shortWriter) Write(b []byte) (int, error) {
+	n, _ := w.f.Write(b[:w.n])
+	return n, errShort
+}
+
+func TestJournalRecovery(t *testing.T) {
+	cases := []struct {
+		name string
+		// damage is applied to the journal before its last packet is written.
+		damage func(t *testing.T, j *Journal)
+	}{
+		{"failed write", func(t *testing.T, j *Journal) {
+			w := j.writer
+			j.writer = NewTraceWriter(shortWriter{f: j.file, n: traceHeaderLen + 1})
+			if err := j.Write(journalPackets(1)[0]); !errors.Is(err, errShort) {
+				t.Fatalf("Write() error = %v, want %v", err, errShort)
+			}
+			j.writer = w
+		}},
+		{"truncated tail", func(t *testing.T, j *Journal) {
+			_ = j.Close()
+			segments, err := journalSegments(j.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = f.Write(make([]byte, traceHeaderLen-1))
+			_ = f.Close()
+			time.Sleep(time.Millisecond)
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			j, err := OpenJournal(dir, JournalOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ps := journalPackets(3)
+			for _, p := range ps[:2] {
+				if err := j.Write(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c.damage(t, j)
+			if err := j.Write(ps[2]); err != nil {
+				t.Fatal(err)
+			}
+			_ = j.Close()
+			if got := readAll(t, dir, time.Time{}); !samePackets(got, ps) {
+				t.Errorf("read %v, want %v", got, ps)
+			}
+		})
+	}
+}
+
+func TestTraceWriterSingleWrite(t *testing.T) {
+	var w countingWriter
+	tw := NewTraceWriter(&w)
+	for _, p := range journalPackets(3) {
+		if err := tw.Write(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if w.calls != 3 {
+		t.Errorf("%d writes for 3 packets, want 3", w.calls)
+	}
+}
+
+type countingWriter struct {
+	calls int
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.calls++
+	return len(b), nil
+}
@@ -0,0 +1,97 @@
+package radio
+
+import (
+	"sync"
+	"time"
+)
+
+// LinkStatus summarizes the signal quality received from one peer.
+type LinkStatus struct {
+	// RSSI is the exponentially weighted average RSSI, in dBm.
+	RSSI float64
+	// Packets is the number of packets received from the peer.
+	Packets int
+	// LastSeen is the time of the most recent packet.
+	LastSeen time.Time
+}
+
+// Margin returns the link margin in dB relative to the given receiver sensitivity.
+func (s LinkStatus) Margin(sensitivity int) float64 {
+	return s.RSSI - float64(sensitivity)
+}
+
+// LinkQuality tracks the received signal strength of each peer.
+// Peers are identified by a caller-chosen key, typically the
+// sender's address as decoded by the protocol in use.
+type LinkQuality struct {
+	// Alpha is the weight given to each new RSSI sample (0 < Alpha <= 1).
+	Alpha float64
+	// Sensitivity is the receiver sensitivity in dBm used by Degraded.
+	Sensitivity int
+	// Clock provides the time packets are seen. If nil, SystemClock is used.
+	Clock Clock
+
+	mu    sync.Mutex
+	peers map[string]*LinkStatus
+}
+
+// NewLinkQuality returns a LinkQuality tracker with the given
+// smoothing factor and receiver sensitivity.
+func NewLinkQuality(alpha float64, sensitivity int) *LinkQuality {
+	return &LinkQuality{
+		Alpha:       alpha,
+		Sensitivity: sensitivity,
+		peers:       make(map[string]*LinkStatus),
+	}
+}
+
+// Update records a packet received from peer with the given RSSI.
+func (q *LinkQuality) Update(peer string, rssi int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	s := q.peers[peer]
+	if s == nil {
+		s = &LinkStatus{RSSI: float64(rssi)}
+		q.peers[peer] = s
+	} else {
+		s.RSSI += q.Alpha * (float64(rssi) - s.RSSI)
+	}
+	s.Packets++
+	s.LastSeen = clocked{q.Clock}.now()
+}
+
+// Status returns the link status of peer, and whether it has been seen.
+func (q *LinkQuality) Status(peer string) (LinkStatus, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	s := q.peers[peer]
+	if s == nil {
+		return LinkStatus{}, false
+	}
+	return *s, true
+}
+
+// Peers returns the link status of every peer that has been seen.
+func (q *LinkQuality) Peers() map[string]LinkStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	m := make(map[string]LinkStatus, len(q.peers))
+	for k, s := range q.peers {
+		m[k] = *s
+	}
+	return m
+}
+
+// Degraded returns the peers whose link margin has fallen below
+// the given number of dB.
+func (q *LinkQuality) Degraded(margin float64) []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var peers []string
+	for k, s := range q.peers {
+		if s.Margin(q.Sensitivity) < margin {
+			peers = append(peers, k)
+		}
+	}
+	return peers
+}
@@ -0,0 +1,134 @@
+package radio
+
+import (
+	"time"
+)
+
+// RSSIReader is implemented by radios that can measure the current
+// received signal strength, in dBm.
+type RSSIReader interface {
+	ReadRSSI() int
+}
+
+func isRSSIReader(r Interface) bool {
+	_, ok := r.(RSSIReader)
+	return ok
+}
+
+// noiseAlpha is the weight given to each new noise sample.
+const noiseAlpha = 1.0 / 16
+
+// Squelch wraps a radio, estimating the noise floor from RSSI samples
+// taken while the radio is idle and discarding receptions whose RSSI
+// is not at least Margin dB above it.
+// Samples are taken whenever a receive times out without a packet,
+// whether or not the radio reports the timeout in its error state,
+// which requires the radio to implement RSSIReader.
+type Squelch struct {
+	Interface
+	// Margin is the required signal strength above the noise floor, in dB.
+	Margin int
+	// Disabled passes all receptions through while still tracking the noise floor.
+	Disabled bool
+
+	floor     float64
+	samples   int
+	discarded int
+}
+
+// NewSquelch returns a Squelch wrapping r with the given margin.
+func NewSquelch(r Interface, margin int) *Squelch {
+	return &Squelch{Interface: r, Margin: margin}
+}
+
+// NoiseFloor returns the estimated noise floor in dBm,
+// or 0 if no samples have been taken.
+func (s *Squelch) NoiseFloor() int {
+	return int(s.floor)
+}
+
+// SetNoiseFloor sets the noise floor estimate, for example from a saved value.
+func (s *Squelch) SetNoiseFloor(dBm int) {
+	s.floor = float64(dBm)
+	s.samples = 1
+}
+
+// Discarded returns the number of receptions rejected by the squelch.
+func (s *Squelch) Discarded() int {
+	return s.discarded
+}
+
+// Sample measures the current RSSI and adds it to the noise floor estimate.
+// It should only be called while no packet is being received.
+func (s *Squelch) Sample() {
+	r, ok := Find(s.Interface, isRSSIReader).(RSSIReader)
+	if !ok {
+		return
+	}
+	rssi := r.ReadRSSI()
+	if s.Error() != nil {
+		return
+	}
+	s.addSample(rssi)
+}
+
+func (s *Squelch) addSample(rssi int) {
+	if s.samples == 0 {
+		s.floor = float64(rssi)
+	} else {
+		s.floor += noiseAlpha * (float64(rssi) - s.floor)
+	}
+	s.samples++
+}
+
+func (s *Squelch) accept(rssi int) bool {
+	if s.Disabled || s.samples == 0 || rssi >= s.NoiseFloor()+s.Margin {
+		return true
+	}
+	s.discarded++
+	return false
+}
+
+// Receive receives a packet that passes the squelch, with the given timeout.
+func (s *Squelch) Receive(timeout time.Duration) ([]byte, int) {
+	deadline := time.Now().Add(timeout)
+	data, rssi := s.Interface.Receive(timeout)
+	return s.filter(deadline, data, rssi)
+}
+
+// SendAndReceive sends data and receives a reply that passes the squelch.
+func (s *Squelch) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	deadline := time.Now().Add(timeout)
+	reply, rssi := s.Interface.SendAndReceive(data, timeout)
+	return s.filter(deadline, reply, rssi)
+}
+
+// filter returns the given reception if it passes the squelch,
+// otherwise it keeps receiving until the deadline.
+func (s *Squelch) filter(deadline time.Time, data []byte, rssi int) ([]byte, int) {
+	for {
+		if err := s.Error(); err != nil {
+			if data == nil && IsTimeout(err) {
+				// Sample with the error state clear, so the read succeeds.
+				s.SetError(nil)
+				s.Sample()
+				if s.Error() == nil {
+					s.SetError(err)
+				}
+			}
+			return data, rssi
+		}
+		if data == nil {
+			s.Sample()
+			return nil, rssi
+		}
+		if s.accept(rssi) {
+			return data, rssi
+		}
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return nil, rssi
+		}
+		data, rssi = s.Interface.Receive(timeout)
+	}
+}
@@ -0,0 +1,68 @@
+package radio_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+// noisyRadio is a simulated radio whose receives all time out,
+// reporting the timeout or another failure in the error state if set.
+// Like a hardware radio, it cannot read RSSI while in the error state.
+type noisyRadio struct {
+	*sim.Radio
+	rssi int
+	err  error
+}
+
+func (r *noisyRadio) Receive(timeout time.Duration) ([]byte, int) {
+	if r.err != nil {
+		r.SetError(r.err)
+	}
+	return nil, 0
+}
+
+func (r *noisyRadio) ReadRSSI() int {
+	if r.Error() != nil {
+		return 0
+	}
+	return r.rssi
+}
+
+func (r *noisyRadio) Unwrap() radio.Interface { return r.Radio }
+
+func TestSquelchSample(t *testing.T) {
+	failure := errors.New("spi failure")
+	timeout := radio.ReceiveTimeoutError{Timeout: time.Millisecond}
+	cases := []struct {
+		name    string
+		err     error
+		sampled bool
+	}{
+		{"silent timeout", nil, true},
+		{"timeout error", timeout, true},
+		{"interrupt timeout", radio.InterruptTimeoutError{Pin: 24, Timeout: time.Millisecond}, true},
+		{"failure", failure, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := &noisyRadio{Radio: sim.NewMedium().NewRadio("r"), rssi: -95, err: c.err}
+			s := radio.NewSquelch(r, 10)
+			s.Receive(time.Millisecond)
+			if err := s.Error(); !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error = %v, want %v", err, c.err)
+			}
+			want := 0
+			if c.sampled {
+				want = -95
+			}
+			if got := s.NoiseFloor(); got != want {
+				t.Errorf("noise floor = %d, want %d", got, want)
+			}
+		})
+	}
+}
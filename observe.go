@@ -0,0 +1,191 @@
+package radio
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Setting identifies a radio setting reported to observers.
+type Setting int
+
+// Settings reported by an Observed radio.
+const (
+	FrequencySetting Setting = iota
+	PowerSetting
+	DataRateSetting
+)
+
+func (s Setting) String() string {
+	switch s {
+	case FrequencySetting:
+		return "frequency"
+	case PowerSetting:
+		return "power"
+	case DataRateSetting:
+		return "data rate"
+	default:
+		return fmt.Sprintf("Setting(%d)", int(s))
+	}
+}
+
+// Change describes a change to a radio setting.
+// Caller is the function and source location that made the change.
+type Change struct {
+	Setting Setting
+	Old     int64
+	New     int64
+	Caller  string
+}
+
+// Observer is called after each change to an observed radio's settings.
+type Observer func(Change)
+
+// Observable is implemented by radios that report changes to their settings.
+type Observable interface {
+	Interface
+	Observe(f Observer)
+}
+
+// Observed wraps a radio to notify observers whenever its frequency,
+// transmit power, or data rate is changed through the wrapper.
+type Observed struct {
+	Interface
+
+	mu        sync.Mutex
+	observers []Observer
+}
+
+// NewObserved returns an observing wrapper for r. The wrapper is a
+// PowerController or DataRater, reporting changes to those settings,
+// only if the wrapped chain includes one, so that wrapping a radio
+// does not change which optional interfaces it appears to implement.
+func NewObserved(r Interface) Observable {
+	o := &Observed{Interface: r}
+	p, power := Find(r, isPowerController).(PowerController)
+	d, rate := Find(r, isDataRater).(DataRater)
+	switch {
+	case power && rate:
+		return observedPowerRate{o, powerObserver{o, p}, rateObserver{o, d}}
+	case power:
+		return observedPower{o, powerObserver{o, p}}
+	case rate:
+		return observedRate{o, rateObserver{o, d}}
+	default:
+		return o
+	}
+}
+
+// Unwrap returns the radio wrapped by o.
+func (o *Observed) Unwrap() Interface {
+	return o.Interface
+}
+
+// Observe registers an observer.
+func (o *Observed) Observe(f Observer) {
+	o.mu.Lock()
+	o.observers = append(o.observers, f)
+	o.mu.Unlock()
+}
+
+func (o *Observed) notify(s Setting, from int64, to int64) {
+	if from == to {
+		return
+	}
+	c := Change{Setting: s, Old: from, New: to, Caller: caller()}
+	o.mu.Lock()
+	observers := append([]Observer(nil), o.observers...)
+	o.mu.Unlock()
+	for _, f := range observers {
+		f(c)
+	}
+}
+
+// caller returns the first function outside this package on the call stack.
+func caller() string {
+	pc := make([]uintptr, 16)
+	n := runtime.Callers(3, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "github.com/ecc1/radio.") {
+			return fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// Init initializes the radio and reports any change of frequency.
+func (o *Observed) Init(frequency uint32) {
+	old := o.Interface.Frequency()
+	o.Interface.Init(frequency)
+	o.notify(FrequencySetting, int64(old), int64(o.Interface.Frequency()))
+}
+
+// SetFrequency sets the frequency and reports the change.
+func (o *Observed) SetFrequency(freq uint32) {
+	old := o.Interface.Frequency()
+	o.Interface.SetFrequency(freq)
+	o.notify(FrequencySetting, int64(old), int64(o.Interface.Frequency()))
+}
+
+func isPowerController(r Interface) bool {
+	_, ok := r.(PowerController)
+	return ok
+}
+
+// powerObserver reports transmit power changes made through an Observed wrapper.
+type powerObserver struct {
+	o     *Observed
+	power PowerController
+}
+
+// TransmitPower returns the transmit power.
+func (p powerObserver) TransmitPower() int {
+	return p.power.TransmitPower()
+}
+
+// SetTransmitPower sets the transmit power and reports the change.
+func (p powerObserver) SetTransmitPower(dBm int) {
+	old := p.power.TransmitPower()
+	p.power.SetTransmitPower(dBm)
+	p.o.notify(PowerSetting, int64(old), int64(p.power.TransmitPower()))
+}
+
+// rateObserver reports data rate changes made through an Observed wrapper.
+type rateObserver struct {
+	o    *Observed
+	rate DataRater
+}
+
+// DataRate returns the data rate.
+func (r rateObserver) DataRate() uint32 {
+	return r.rate.DataRate()
+}
+
+// SetDataRate sets the data rate and reports the change.
+func (r rateObserver) SetDataRate(bitsPerSecond uint32) {
+	old := r.rate.DataRate()
+	r.rate.SetDataRate(bitsPerSecond)
+	r.o.notify(DataRateSetting, int64(old), int64(r.rate.DataRate()))
+}
+
+// Observed wrappers for chains that include a PowerController, a DataRater, or both.
+type (
+	observedPower struct {
+		*Observed
+		powerObserver
+	}
+	observedRate struct {
+		*Observed
+		rateObserver
+	}
+	observedPowerRate struct {
+		*Observed
+		powerObserver
+		rateObserver
+	}
+)
@@ -0,0 +1,63 @@
+package radio_test
+
+import (
+	"testing"
+
+	"github.com/ecc1/radio"
+)
+
+func TestObservedOptionalInterfaces(t *testing.T) {
+	_, a, _ := simPair(t)
+	cases := []struct {
+		name  string
+		inner radio.Interface
+		rate  bool
+	}{
+		{"Plain", a, false},
+		{"DataRater", &rateRadio{Radio: a, rate: 38400}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := radio.NewObserved(c.inner)
+			if _, ok := o.(radio.DataRater); ok != c.rate {
+				t.Errorf("DataRater = %v, want %v", ok, c.rate)
+			}
+			if _, ok := o.(radio.PowerController); ok {
+				t.Error("wrapper claims to be a PowerController")
+			}
+			if got := radio.CapabilitiesOf(o).DataRate; got != c.rate {
+				t.Errorf("CapabilitiesOf DataRate = %v, want %v", got, c.rate)
+			}
+			o.SetError(nil)
+			radio.SendOn(o, 0, 9600, []byte{1})
+			if got := o.Error() == radio.ErrNoDataRate; got == c.rate {
+				t.Errorf("SendOn error = %v", o.Error())
+			}
+		})
+	}
+}
+
+func TestObservedNotifies(t *testing.T) {
+	_, a, _ := simPair(t)
+	o := radio.NewObserved(&rateRadio{Radio: a, rate: 38400})
+	var changes []radio.Change
+	o.Observe(func(c radio.Change) { changes = append(changes, c) })
+	o.SetFrequency(916600000)
+	o.SetFrequency(916600000)
+	o.(radio.DataRater).SetDataRate(9600)
+	want := []radio.Change{
+		{Setting: radio.FrequencySetting, Old: 916500000, New: 916600000},
+		{Setting: radio.DataRateSetting, Old: 38400, New: 9600},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(changes), len(want))
+	}
+	for i, c := range changes {
+		if c.Setting != want[i].Setting || c.Old != want[i].Old || c.New != want[i].New {
+			t.Errorf("change %d = %+v, want %+v", i, c, want[i])
+		}
+		if c.Caller == "" {
+			t.Errorf("change %d has no caller", i)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package radio
+
+import (
+	"sync"
+	"time"
+)
+
+// LoggedPacket is a packet recorded by a PacketLog.
+type LoggedPacket struct {
+	Packet
+	Sent bool
+}
+
+// PacketLog wraps a radio to keep a ring buffer of the most recent
+// packets sent and received through it, for inclusion in diagnostics.
+type PacketLog struct {
+	Interface
+
+	mu      sync.Mutex
+	packets []LoggedPacket
+	next    int
+	full    bool
+	clocked
+}
+
+// NewPacketLog returns a PacketLog for r that keeps the last n packets.
+func NewPacketLog(r Interface, n int) *PacketLog {
+	return &PacketLog{Interface: r, packets: make([]LoggedPacket, n)}
+}
+
+// WithPacketLog returns middleware that keeps the last n packets.
+func WithPacketLog(n int) Middleware {
+	return func(r Interface) Interface { return NewPacketLog(r, n) }
+}
+
+// Unwrap returns the radio wrapped by l.
+func (l *PacketLog) Unwrap() Interface {
+	return l.Interface
+}
+
+// SetClock sets the Clock that timestamps logged packets.
+// A nil Clock means SystemClock.
+func (l *PacketLog) SetClock(clock Clock) {
+	l.clock = clock
+}
+
+// Packets returns the logged packets, oldest first.
+func (l *PacketLog) Packets() []LoggedPacket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.full {
+		return append([]LoggedPacket(nil), l.packets[:l.next]...)
+	}
+	return append(append([]LoggedPacket(nil), l.packets[l.next:]...), l.packets[:l.next]...)
+}
+
+func (l *PacketLog) add(data []byte, rssi int, sent bool) {
+	if data == nil || len(l.packets) == 0 {
+		return
+	}
+	p := LoggedPacket{
+		Packet: Packet{
+			Data:      append([]byte(nil), data...),
+			RSSI:      rssi,
+			Frequency: l.Frequency(),
+			Time:      l.now(),
+		},
+		Sent: sent,
+	}
+	l.mu.Lock()
+	l.packets[l.next] = p
+	l.next++
+	if l.next == len(l.packets) {
+		l.next = 0
+		l.full = true
+	}
+	l.mu.Unlock()
+}
+
+// Send transmits data and logs it.
+func (l *PacketLog) Send(data []byte) {
+	l.Interface.Send(data)
+	if l.Error() == nil {
+		l.add(data, 0, true)
+	}
+}
+
+// Receive listens for a packet and logs it.
+func (l *PacketLog) Receive(timeout time.Duration) ([]byte, int) {
+	data, rssi := l.Interface.Receive(timeout)
+	l.add(data, rssi, false)
+	return data, rssi
+}
+
+// SendAndReceive transmits data, listens for a response, and logs both.
+func (l *PacketLog) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	resp, rssi := l.Interface.SendAndReceive(data, timeout)
+	l.add(data, 0, true)
+	l.add(resp, rssi, false)
+	return resp, rssi
+}
+
+func isPacketLog(r Interface) bool {
+	_, ok := r.(*PacketLog)
+	return ok
+}
@@ -0,0 +1,105 @@
+package radio
+
+import (
+	"fmt"
+	"time"
+)
+
+// PayloadFlavor is implemented by flavors that declare the size of the
+// chip's transmit FIFO and the longest payload it can send.
+// A payload longer than the FIFO but no longer than the maximum
+// must be sent by refilling the FIFO while transmitting.
+type PayloadFlavor interface {
+	MaxPayloadLength() int
+	FIFOSize() int
+}
+
+// PacketTooLargeError indicates an attempt to send a packet longer
+// than the radio can transmit.
+type PacketTooLargeError struct {
+	Length int
+	Max    int
+}
+
+func (e PacketTooLargeError) Error() string {
+	return fmt.Sprintf("packet length %d exceeds maximum of %d bytes", e.Length, e.Max)
+}
+
+// MaxPayloadLength returns the longest payload declared by the flavor,
+// or 0 if it does not declare one.
+func (h *Hardware) MaxPayloadLength() int {
+	if f, ok := h.flavor.(PayloadFlavor); ok {
+		return f.MaxPayloadLength()
+	}
+	return 0
+}
+
+// CheckPayload validates the length of a payload about to be sent
+// against the limits declared by the flavor. If it is too long,
+// the error state is set to a PacketTooLargeError. Otherwise it
+// reports whether the payload is longer than the FIFO, in which case
+// the driver must use its streaming transmit path.
+// Drivers call it at the start of Send.
+func (h *Hardware) CheckPayload(data []byte) (streaming bool) {
+	f, ok := h.flavor.(PayloadFlavor)
+	if !ok {
+		return false
+	}
+	n := len(data)
+	if max := f.MaxPayloadLength(); max > 0 && n > max {
+		h.err = PacketTooLargeError{Length: n, Max: max}
+		return false
+	}
+	size := f.FIFOSize()
+	return size > 0 && n > size
+}
+
+// PayloadLimit wraps a radio so that Send and SendAndReceive set the
+// error state to a PacketTooLargeError, instead of transmitting,
+// when given a payload longer than Max.
+type PayloadLimit struct {
+	Interface
+	Max int
+}
+
+// NewPayloadLimit returns a PayloadLimit for r. A non-positive max
+// means the MaxPacketLength reported by CapabilitiesOf(r).
+func NewPayloadLimit(r Interface, max int) *PayloadLimit {
+	if max <= 0 {
+		max = CapabilitiesOf(r).MaxPacketLength
+	}
+	return &PayloadLimit{Interface: r, Max: max}
+}
+
+// WithPayloadLimit returns middleware that enforces a maximum payload length.
+func WithPayloadLimit(max int) Middleware {
+	return func(r Interface) Interface { return NewPayloadLimit(r, max) }
+}
+
+// Unwrap returns the radio wrapped by p.
+func (p *PayloadLimit) Unwrap() Interface {
+	return p.Interface
+}
+
+func (p *PayloadLimit) check(data []byte) bool {
+	if p.Max > 0 && len(data) > p.Max {
+		p.SetError(PacketTooLargeError{Length: len(data), Max: p.Max})
+		return false
+	}
+	return true
+}
+
+// Send transmits data if it is not too long.
+func (p *PayloadLimit) Send(data []byte) {
+	if p.check(data) {
+		p.Interface.Send(data)
+	}
+}
+
+// SendAndReceive transmits data, if it is not too long, and listens for a response.
+func (p *PayloadLimit) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	if !p.check(data) {
+		return nil, 0
+	}
+	return p.Interface.SendAndReceive(data, timeout)
+}
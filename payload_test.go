@@ -0,0 +1,74 @@
+package radio_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+// countingSender counts the packets it is asked to transmit
+// and reports a maximum packet length of 8 bytes.
+type countingSender struct {
+	*sim.Radio
+	sends int
+}
+
+func (r *countingSender) Send(data []byte) { r.sends++ }
+
+func (r *countingSender) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	r.sends++
+	return nil, 0
+}
+
+func (r *countingSender) Capabilities() radio.Capabilities {
+	return radio.Capabilities{MaxPacketLength: 8}
+}
+
+func (r *countingSender) Unwrap() radio.Interface { return r.Radio }
+
+func TestPayloadLimit(t *testing.T) {
+	cases := []struct {
+		name string
+		max  int
+		n    int
+		err  error
+	}{
+		{"within limit", 4, 4, nil},
+		{"oversize", 4, 5, radio.PacketTooLargeError{Length: 5, Max: 4}},
+		{"capabilities limit", 0, 8, nil},
+		{"oversize for capabilities", 0, 9, radio.PacketTooLargeError{Length: 9, Max: 8}},
+		{"explicit limit overrides capabilities", 16, 12, nil},
+	}
+	for _, c := range cases {
+		for _, exchange := range []bool{false, true} {
+			name := c.name
+			if exchange {
+				name += " reply"
+			}
+			t.Run(name, func(t *testing.T) {
+				_, a, _ := simPair(t)
+				inner := &countingSender{Radio: a}
+				p := radio.NewPayloadLimit(radio.NewSquelch(inner, 10), c.max)
+				data := make([]byte, c.n)
+				if exchange {
+					p.SendAndReceive(data, time.Millisecond)
+				} else {
+					p.Send(data)
+				}
+				if err := p.Error(); !reflect.DeepEqual(err, c.err) {
+					t.Errorf("error = %v, want %v", err, c.err)
+				}
+				sends := 1
+				if c.err != nil {
+					sends = 0
+				}
+				if inner.sends != sends {
+					t.Errorf("%d transmissions, want %d", inner.sends, sends)
+				}
+			})
+		}
+	}
+}
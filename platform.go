@@ -0,0 +1,9 @@
+package radio
+
+import (
+	"errors"
+)
+
+// ErrUnsupportedPlatform indicates an operation that needs Linux
+// hardware support, such as GPIO access or real-time scheduling.
+var ErrUnsupportedPlatform = errors.New("radio hardware is only supported on Linux")
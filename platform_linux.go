@@ -0,0 +1,31 @@
+//go:build linux
+// +build linux
+
+package radio
+
+import (
+	"github.com/ecc1/gpio"
+	"github.com/ecc1/spi"
+)
+
+func openSPI(device string, speed int, customCS int) (spiDevice, error) {
+	dev, err := spi.Open(device, speed, customCS)
+	if err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+func openOutput(pin int, activeLow bool, initialValue bool) (outputPin, error) {
+	p, err := gpio.Output(pin, activeLow, initialValue)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SPIDevice returns the radio's SPI device, or nil in dry-run mode.
+func (h *Hardware) SPIDevice() *spi.Device {
+	dev, _ := h.device.(*spi.Device)
+	return dev
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package radio
+
+// On platforms other than Linux, there is no SPI or GPIO support.
+// Devices open as stubs so that applications still build and their
+// other code paths can be developed: transfers are discarded,
+// reads return zeros, and interrupt waits sleep for their timeout.
+func openSPI(device string, speed int, customCS int) (spiDevice, error) {
+	return &dryRunDevice{stub: true}, nil
+}
+
+func openOutput(pin int, activeLow bool, initialValue bool) (outputPin, error) {
+	return nil, ErrUnsupportedPlatform
+}
@@ -0,0 +1,168 @@
+package radio
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// PollDevice describes a remote device polled by a Poller.
+// A zero or negative Interval is replaced by DefaultPollerInterval.
+type PollDevice struct {
+	Name      string
+	Frequency uint32
+	Request   []byte
+	Interval  time.Duration
+	Timeout   time.Duration
+	// Decode, if not nil, converts the device's replies
+	// from its own encoding before they are delivered.
+	Decode func(data []byte) ([]byte, error)
+}
+
+// DefaultPollerInterval is used for a PollDevice without an Interval.
+const DefaultPollerInterval = time.Second
+
+// PollResponse is the result of polling a device.
+// Data is nil if the device did not reply within its timeout.
+// If the device's Decode function fails, Data holds the undecoded reply
+// and Err the decoding error; the device is polled again at its usual interval.
+// After an error other than a timeout, the device is polled again
+// with an increasing delay in place of its interval; a canceled wait,
+// as after CancelWaits, stops the Poller.
+type PollResponse struct {
+	Device string
+	Data   []byte
+	RSSI   int
+	Time   time.Time
+	Err    error
+}
+
+// PollerOptions configures a Poller.
+type PollerOptions struct {
+	// Clock provides the time. If nil, SystemClock is used.
+	Clock Clock
+}
+
+type pollState struct {
+	PollDevice
+	next    time.Time
+	backoff errorBackoff
+}
+
+// Poller polls several remote devices over a single radio,
+// each on its own frequency and interval, and delivers their
+// responses tagged with the device name.
+// Devices that fall due at the same time are polled in the order given.
+type Poller struct {
+	radio     Interface
+	devices   []*pollState
+	responses chan PollResponse
+	done      chan struct{}
+	stop      sync.Once
+	wg        sync.WaitGroup
+	clocked
+}
+
+// NewPoller starts polling the given devices over r.
+// The radio must not be used by other goroutines until the Poller is stopped.
+func NewPoller(r Interface, devices []PollDevice, opts PollerOptions) *Poller {
+	p := &Poller{
+		radio:     r,
+		clocked:   clocked{opts.Clock},
+		responses: make(chan PollResponse, len(devices)),
+		done:      make(chan struct{}),
+	}
+	start := p.now()
+	for _, d := range devices {
+		if d.Interval <= 0 {
+			d.Interval = DefaultPollerInterval
+		}
+		p.devices = append(p.devices, &pollState{PollDevice: d, next: start})
+	}
+	p.wg.Add(1)
+	go p.loop()
+	return p
+}
+
+// Responses returns the channel on which responses are delivered.
+// It is closed when the Poller stops.
+func (p *Poller) Responses() <-chan PollResponse {
+	return p.responses
+}
+
+// Stop stops the Poller after any poll in progress completes.
+// It may be called more than once.
+func (p *Poller) Stop() {
+	p.stop.Do(func() { close(p.done) })
+	p.wg.Wait()
+}
+
+func (p *Poller) loop() {
+	defer p.wg.Done()
+	defer close(p.responses)
+	if len(p.devices) == 0 {
+		<-p.done
+		return
+	}
+	for {
+		d := p.due()
+		select {
+		case <-p.after(p.until(d.next)):
+		case <-p.done:
+			return
+		}
+		resp, err := p.poll(d)
+		d.next = d.next.Add(d.Interval)
+		if t := p.now(); d.next.Before(t) {
+			// Skip polls that were missed rather than bunching them up.
+			d.next = t
+		}
+		if err != nil && !IsTimeout(err) {
+			if retry := resp.Time.Add(d.backoff.next()); d.next.Before(retry) {
+				d.next = retry
+			}
+		} else {
+			d.backoff.reset()
+		}
+		select {
+		case p.responses <- resp:
+		case <-p.done:
+			return
+		}
+		if errors.Is(err, ErrWaitCanceled) {
+			return
+		}
+	}
+}
+
+// due returns the device whose next poll is earliest.
+func (p *Poller) due() *pollState {
+	d := p.devices[0]
+	for _, e := range p.devices[1:] {
+		if e.next.Before(d.next) {
+			d = e
+		}
+	}
+	return d
+}
+
+// poll polls a device and returns its response along with
+// the radio error, if any, which determines the retry delay.
+func (p *Poller) poll(d *pollState) (PollResponse, error) {
+	r := p.radio
+	if d.Frequency != 0 && d.Frequency != r.Frequency() {
+		r.SetFrequency(d.Frequency)
+	}
+	data, rssi := r.SendAndReceive(d.Request, d.Timeout)
+	err := r.Error()
+	r.SetError(nil)
+	resp := PollResponse{Device: d.Name, Data: data, RSSI: rssi, Time: p.now(), Err: err}
+	if err == nil && data != nil && d.Decode != nil {
+		if decoded, e := d.Decode(data); e != nil {
+			resp.Err = e
+		} else {
+			resp.Data = decoded
+		}
+	}
+	return resp, err
+}
@@ -0,0 +1,184 @@
+package radio_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+func TestPoller(t *testing.T) {
+	const (
+		pumpFreq = 916500000
+		cgmFreq  = 868350000
+	)
+	m := sim.NewMedium()
+	r, pump, cgm := m.NewRadio("gateway"), m.NewRadio("pump"), m.NewRadio("cgm")
+	r.Init(pumpFreq)
+	pump.Init(pumpFreq)
+	cgm.Init(cgmFreq)
+	stop := make(chan struct{})
+	defer close(stop)
+	go echo(pump, stop)
+	go echo(cgm, stop)
+	devices := []radio.PollDevice{
+		{Name: "pump", Frequency: pumpFreq, Request: []byte{1}, Interval: 20 * time.Millisecond, Timeout: 50 * time.Millisecond},
+		{Name: "cgm", Frequency: cgmFreq, Request: []byte{2}, Interval: 20 * time.Millisecond, Timeout: 50 * time.Millisecond},
+		{Name: "weather", Frequency: 433920000, Request: []byte{3}, Interval: 20 * time.Millisecond, Timeout: 5 * time.Millisecond},
+	}
+	want := map[string][]byte{"pump": {1}, "cgm": {2}, "weather": nil}
+	p := radio.NewPoller(r, devices, radio.PollerOptions{})
+	defer p.Stop()
+	counts := make(map[string]int)
+	replies := make(map[string]int)
+	for i := 0; i < 5*len(devices); i++ {
+		var resp radio.PollResponse
+		select {
+		case resp = <-p.Responses():
+		case <-time.After(time.Second):
+			t.Fatalf("no response after %v", counts)
+		}
+		if resp.Err != nil {
+			t.Errorf("%s: %v", resp.Device, resp.Err)
+		}
+		// The simulated echo can miss a request that arrives
+		// between its Receive calls, so allow missed replies.
+		if resp.Data != nil && !bytes.Equal(resp.Data, want[resp.Device]) {
+			t.Errorf("%s replied %v, want %v", resp.Device, resp.Data, want[resp.Device])
+		}
+		counts[resp.Device]++
+		if resp.Data != nil {
+			replies[resp.Device]++
+		}
+	}
+	for name, data := range want {
+		if counts[name] == 0 {
+			t.Errorf("%s was never polled: %v", name, counts)
+		}
+		if data != nil && replies[name] == 0 {
+			t.Errorf("%s never replied: %v", name, replies)
+		}
+	}
+}
+
+func TestPollerErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		setup   func(r *sim.Radio)
+		want    error
+		stopped bool
+	}{
+		{"closed", func(r *sim.Radio) { r.Close() }, sim.ErrClosed, false},
+		{"canceled", func(r *sim.Radio) { r.SetError(radio.ErrWaitCanceled) }, radio.ErrWaitCanceled, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			c.setup(a)
+			p := radio.NewPoller(a, []radio.PollDevice{{Name: "d", Request: []byte{1}, Interval: time.Millisecond, Timeout: time.Millisecond}}, radio.PollerOptions{})
+			defer p.Stop()
+			timeout := time.After(100 * time.Millisecond)
+			n := 0
+		loop:
+			for {
+				select {
+				case resp, ok := <-p.Responses():
+					if !ok {
+						break loop
+					}
+					n++
+					if !errors.Is(resp.Err, c.want) {
+						t.Errorf("response error = %v, want %v", resp.Err, c.want)
+					}
+				case <-timeout:
+					break loop
+				}
+			}
+			switch {
+			case c.stopped && n != 1:
+				t.Errorf("received %d responses, want 1 before stopping", n)
+			case n == 0 || n > 5:
+				t.Errorf("received %d responses in 100ms, want a few", n)
+			}
+		})
+	}
+}
+
+func TestPollerDecode(t *testing.T) {
+	errBad := errors.New("bad reply")
+	upper := func(data []byte) ([]byte, error) { return bytes.ToUpper(data), nil }
+	fail := func([]byte) ([]byte, error) { return nil, errBad }
+	cases := []struct {
+		name    string
+		request string
+		decode  func([]byte) ([]byte, error)
+		want    string
+		err     error
+	}{
+		{"no decoder", "abc", nil, "abc", nil},
+		{"decoded", "abc", upper, "ABC", nil},
+		{"malformed", "abc", fail, "abc", errBad},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, b := simPair(t)
+			stop := make(chan struct{})
+			defer close(stop)
+			go echo(b, stop)
+			d := radio.PollDevice{Name: "d", Request: []byte(c.request), Interval: time.Millisecond, Timeout: 20 * time.Millisecond, Decode: c.decode}
+			p := radio.NewPoller(a, []radio.PollDevice{d}, radio.PollerOptions{})
+			defer p.Stop()
+			timeout := time.After(time.Second)
+			for {
+				var resp radio.PollResponse
+				select {
+				case resp = <-p.Responses():
+				case <-timeout:
+					t.Fatal("device never replied")
+				}
+				// The simulated echo can miss a request, so wait for a reply.
+				if resp.Data == nil {
+					continue
+				}
+				if string(resp.Data) != c.want {
+					t.Errorf("data = %q, want %q", resp.Data, c.want)
+				}
+				if resp.Err != c.err {
+					t.Errorf("error = %v, want %v", resp.Err, c.err)
+				}
+				return
+			}
+		})
+	}
+}
+
+func TestPollerDefaultInterval(t *testing.T) {
+	_, a, _ := simPair(t)
+	clock := radio.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	d := radio.PollDevice{Name: "d", Request: []byte{1}, Timeout: time.Millisecond}
+	p := radio.NewPoller(a, []radio.PollDevice{d}, radio.PollerOptions{Clock: clock})
+	defer p.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-p.Responses():
+		case <-time.After(time.Second):
+			t.Fatalf("no response to poll %d", i)
+		}
+		waitFor(t, func() bool { return clock.Waiters() == 1 })
+		clock.Advance(radio.DefaultPollerInterval - time.Millisecond)
+		if clock.Waiters() != 1 {
+			t.Fatalf("poll %d: device polled again before %v", i, radio.DefaultPollerInterval)
+		}
+		clock.Advance(time.Millisecond)
+	}
+}
+
+func TestPollerStopTwice(t *testing.T) {
+	_, a, _ := simPair(t)
+	p := radio.NewPoller(a, nil, radio.PollerOptions{})
+	p.Stop()
+	p.Stop()
+}
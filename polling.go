@@ -0,0 +1,77 @@
+package radio
+
+import (
+	"time"
+)
+
+// InterruptStatusFlavor is implemented by flavors whose chips report
+// the condition signaled on the interrupt line in a status register.
+// It allows the radio to be used with no interrupt line connected,
+// by opening it with a negative InterruptPin; the interrupt is
+// considered active when any of the mask bits are set.
+type InterruptStatusFlavor interface {
+	InterruptStatus() (addr byte, mask byte)
+}
+
+// DefaultPollInterval is the interval at which the interrupt status
+// register is read when no interrupt line is connected, and at which
+// AwaitStateChange and AwaitTransmit check the chip.
+const DefaultPollInterval = time.Millisecond
+
+// SetPollInterval sets the interval at which the interrupt status
+// register is read when no interrupt line is connected, and at which
+// AwaitStateChange and AwaitTransmit check the chip.
+// Zero makes them wait out the timeout between checks.
+func (h *Hardware) SetPollInterval(interval time.Duration) {
+	h.pollInterval = interval
+}
+
+// SetClock sets the Clock that times polled waits, such as those of
+// AwaitTransmit and AwaitStateChange and of interrupt waits without
+// an interrupt line, typically to a FakeClock in tests.
+// A nil Clock means SystemClock.
+func (h *Hardware) SetClock(clock Clock) {
+	h.clock = clock
+}
+
+func (h *Hardware) readInterruptStatus() bool {
+	f, ok := h.flavor.(InterruptStatusFlavor)
+	if !ok {
+		return false
+	}
+	addr, mask := f.InterruptStatus()
+	return h.ReadRegister(addr)&mask != 0
+}
+
+// pollInterrupt waits for the interrupt condition by polling the status register.
+// Without an InterruptStatusFlavor, it simply waits for the timeout to expire,
+// still waking at the poll interval to check for cancellation.
+func (h *Hardware) pollInterrupt(timeout time.Duration) error {
+	deadline := h.now().Add(timeout)
+	_, ok := h.flavor.(InterruptStatusFlavor)
+	for {
+		if ok {
+			active := h.readInterruptStatus()
+			if h.Error() != nil {
+				return h.Error()
+			}
+			if active {
+				return nil
+			}
+		}
+		if h.ctx != nil && h.ctx.Err() != nil {
+			return h.ctx.Err()
+		}
+		if h.waitsCanceled() {
+			return ErrWaitCanceled
+		}
+		left := h.until(deadline)
+		if left <= 0 {
+			return InterruptTimeoutError{Pin: h.settings.InterruptPin, Timeout: timeout}
+		}
+		if h.pollInterval > 0 && left > h.pollInterval {
+			left = h.pollInterval
+		}
+		h.sleep(left)
+	}
+}
@@ -0,0 +1,106 @@
+package radio
+
+import (
+	"time"
+)
+
+// PreambleQualitySetter is implemented by radios that can require a
+// minimum preamble quality before accepting a sync word, such as the
+// CC1101's PQT setting. Higher thresholds reject more false syncs.
+type PreambleQualitySetter interface {
+	SetPreambleQuality(threshold int)
+}
+
+// PreambleGateConfig configures a PreambleGate.
+type PreambleGateConfig struct {
+	// Quality is the preamble quality threshold applied to radios
+	// that implement PreambleQualitySetter.
+	Quality int
+	// MinRSSI and MinLength are checked in software for every packet;
+	// packets that are weaker or shorter are treated as false syncs.
+	// Zero disables the corresponding check.
+	MinRSSI   int
+	MinLength int
+}
+
+// PreambleGate wraps a radio to suppress packets produced by false sync
+// detections, which are common at high receiver sensitivity. It uses the
+// radio's preamble quality threshold when available, and discards
+// implausible packets in software in any case.
+type PreambleGate struct {
+	Interface
+	config    PreambleGateConfig
+	discarded int
+}
+
+// NewPreambleGate returns a PreambleGate for r with the given configuration.
+func NewPreambleGate(r Interface, config PreambleGateConfig) *PreambleGate {
+	g := &PreambleGate{Interface: r, config: config}
+	g.apply()
+	return g
+}
+
+// Unwrap returns the radio wrapped by g.
+func (g *PreambleGate) Unwrap() Interface {
+	return g.Interface
+}
+
+// Discarded returns the number of packets rejected in software.
+func (g *PreambleGate) Discarded() int {
+	return g.discarded
+}
+
+func isPreambleQualitySetter(r Interface) bool {
+	_, ok := r.(PreambleQualitySetter)
+	return ok
+}
+
+func (g *PreambleGate) apply() {
+	if p, ok := Find(g.Interface, isPreambleQualitySetter).(PreambleQualitySetter); ok {
+		p.SetPreambleQuality(g.config.Quality)
+	}
+}
+
+// Init initializes the radio and reapplies the preamble quality threshold.
+func (g *PreambleGate) Init(frequency uint32) {
+	g.Interface.Init(frequency)
+	g.apply()
+}
+
+func (g *PreambleGate) plausible(data []byte, rssi int) bool {
+	if g.config.MinLength != 0 && len(data) < g.config.MinLength {
+		return false
+	}
+	if g.config.MinRSSI != 0 && rssi < g.config.MinRSSI {
+		return false
+	}
+	return true
+}
+
+// Receive receives a packet that passes the gate, with the given timeout.
+func (g *PreambleGate) Receive(timeout time.Duration) ([]byte, int) {
+	deadline := time.Now().Add(timeout)
+	data, rssi := g.Interface.Receive(timeout)
+	return g.filter(deadline, data, rssi)
+}
+
+// SendAndReceive sends data and receives a reply that passes the gate.
+func (g *PreambleGate) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	deadline := time.Now().Add(timeout)
+	reply, rssi := g.Interface.SendAndReceive(data, timeout)
+	return g.filter(deadline, reply, rssi)
+}
+
+func (g *PreambleGate) filter(deadline time.Time, data []byte, rssi int) ([]byte, int) {
+	for {
+		if data == nil || g.Error() != nil || g.plausible(data, rssi) {
+			return data, rssi
+		}
+		g.discarded++
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return nil, rssi
+		}
+		data, rssi = g.Interface.Receive(timeout)
+	}
+}
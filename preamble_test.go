@@ -0,0 +1,98 @@
+package radio_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+type scriptedPacket struct {
+	data []byte
+	rssi int
+}
+
+// scriptedRadio receives the given packets in order, then times out.
+// It records the preamble quality thresholds set on it.
+type scriptedRadio struct {
+	*sim.Radio
+	packets []scriptedPacket
+	quality []int
+}
+
+func (r *scriptedRadio) Receive(time.Duration) ([]byte, int) {
+	if len(r.packets) == 0 {
+		return nil, 0
+	}
+	p := r.packets[0]
+	r.packets = r.packets[1:]
+	return p.data, p.rssi
+}
+
+func (r *scriptedRadio) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	r.Send(data)
+	return r.Receive(timeout)
+}
+
+func (r *scriptedRadio) SetPreambleQuality(threshold int) {
+	r.quality = append(r.quality, threshold)
+}
+
+func (r *scriptedRadio) Unwrap() radio.Interface { return r.Radio }
+
+func TestPreambleGate(t *testing.T) {
+	short := scriptedPacket{[]byte{1}, -60}
+	weak := scriptedPacket{[]byte{1, 2, 3, 4}, -110}
+	good := scriptedPacket{[]byte{1, 2, 3, 4}, -60}
+	cases := []struct {
+		name      string
+		config    radio.PreambleGateConfig
+		packets   []scriptedPacket
+		want      []byte
+		discarded int
+	}{
+		{"off", radio.PreambleGateConfig{}, []scriptedPacket{short, good}, short.data, 0},
+		{"short", radio.PreambleGateConfig{MinLength: 4}, []scriptedPacket{short, good}, good.data, 1},
+		{"weak", radio.PreambleGateConfig{MinRSSI: -100}, []scriptedPacket{weak, good}, good.data, 1},
+		{"both", radio.PreambleGateConfig{MinLength: 4, MinRSSI: -100}, []scriptedPacket{short, weak, good}, good.data, 2},
+		{"all rejected", radio.PreambleGateConfig{MinLength: 4, MinRSSI: -100}, []scriptedPacket{short, weak}, nil, 2},
+		{"nothing received", radio.PreambleGateConfig{MinLength: 4}, nil, nil, 0},
+	}
+	for _, c := range cases {
+		for _, exchange := range []bool{false, true} {
+			name := c.name
+			if exchange {
+				name += " reply"
+			}
+			t.Run(name, func(t *testing.T) {
+				_, a, _ := simPair(t)
+				r := &scriptedRadio{Radio: a, packets: c.packets}
+				g := radio.NewPreambleGate(r, c.config)
+				var data []byte
+				if exchange {
+					data, _ = g.SendAndReceive([]byte{0}, 100*time.Millisecond)
+				} else {
+					data, _ = g.Receive(100 * time.Millisecond)
+				}
+				if !reflect.DeepEqual(data, c.want) {
+					t.Errorf("received % X, want % X", data, c.want)
+				}
+				if n := g.Discarded(); n != c.discarded {
+					t.Errorf("discarded %d packets, want %d", n, c.discarded)
+				}
+			})
+		}
+	}
+}
+
+func TestPreambleGateQuality(t *testing.T) {
+	_, a, _ := simPair(t)
+	r := &scriptedRadio{Radio: a}
+	g := radio.NewPreambleGate(radio.NewSquelch(r, 10), radio.PreambleGateConfig{Quality: 3})
+	g.Init(916500000)
+	if want := []int{3, 3}; !reflect.DeepEqual(r.quality, want) {
+		t.Errorf("preamble quality set to %v, want %v", r.quality, want)
+	}
+}
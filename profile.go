@@ -0,0 +1,135 @@
+package radio
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Profile is a named operating configuration.
+// Zero Frequency and DataRate fields leave those settings unchanged,
+// as does TransmitPower unless SetPower is true.
+type Profile struct {
+	Frequency     uint32
+	DataRate      uint32
+	TransmitPower int
+	SetPower      bool
+}
+
+// ErrNoTransmitPower indicates that a radio does not support setting its transmit power.
+var ErrNoTransmitPower = errors.New("radio does not support setting the transmit power")
+
+// UnknownProfileError indicates a profile name that has not been defined.
+type UnknownProfileError string
+
+func (e UnknownProfileError) Error() string {
+	return fmt.Sprintf("unknown radio profile %q", string(e))
+}
+
+// Profiles manages named profiles for a radio, such as "pairing",
+// "normal", and "long-range", and switches between them.
+type Profiles struct {
+	radio Interface
+
+	mu       sync.Mutex
+	profiles map[string]Profile
+	current  string
+}
+
+// NewProfiles returns a profile manager for r with no profiles defined.
+func NewProfiles(r Interface) *Profiles {
+	return &Profiles{radio: r, profiles: make(map[string]Profile)}
+}
+
+// Define adds or replaces a named profile.
+func (p *Profiles) Define(name string, prof Profile) {
+	p.mu.Lock()
+	p.profiles[name] = prof
+	p.mu.Unlock()
+}
+
+// Current returns the name of the profile in use,
+// or the empty string if none has been selected.
+func (p *Profiles) Current() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+// UseProfile applies the named profile to the radio.
+func (p *Profiles) UseProfile(name string) error {
+	p.mu.Lock()
+	prof, ok := p.profiles[name]
+	p.mu.Unlock()
+	if !ok {
+		return UnknownProfileError(name)
+	}
+	err := p.apply(prof)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.current = name
+	p.mu.Unlock()
+	return nil
+}
+
+// WithProfile applies the named profile, calls f, and then restores the
+// settings and current profile name that were in effect beforehand.
+func (p *Profiles) WithProfile(name string, f func()) error {
+	saved := p.snapshot()
+	prev := p.Current()
+	err := p.UseProfile(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = p.apply(saved)
+		p.mu.Lock()
+		p.current = prev
+		p.mu.Unlock()
+	}()
+	f()
+	return nil
+}
+
+// snapshot returns a profile holding the radio's current settings.
+func (p *Profiles) snapshot() Profile {
+	prof := Profile{Frequency: p.radio.Frequency()}
+	if d, ok := Find(p.radio, isDataRater).(DataRater); ok {
+		prof.DataRate = d.DataRate()
+	}
+	if c, ok := Find(p.radio, isPowerController).(PowerController); ok {
+		prof.TransmitPower = c.TransmitPower()
+		prof.SetPower = true
+	}
+	return prof
+}
+
+func (p *Profiles) apply(prof Profile) error {
+	r := p.radio
+	var d DataRater
+	if prof.DataRate != 0 {
+		var ok bool
+		if d, ok = Find(r, isDataRater).(DataRater); !ok {
+			return ErrNoDataRate
+		}
+	}
+	var c PowerController
+	if prof.SetPower {
+		var ok bool
+		if c, ok = Find(r, isPowerController).(PowerController); !ok {
+			return ErrNoTransmitPower
+		}
+	}
+	if prof.Frequency != 0 && prof.Frequency != r.Frequency() {
+		r.SetFrequency(prof.Frequency)
+	}
+	if d != nil && prof.DataRate != d.DataRate() {
+		d.SetDataRate(prof.DataRate)
+	}
+	if c != nil && prof.TransmitPower != c.TransmitPower() {
+		c.SetTransmitPower(prof.TransmitPower)
+	}
+	return r.Error()
+}
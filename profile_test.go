@@ -0,0 +1,100 @@
+package radio_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+func TestProfiles(t *testing.T) {
+	const nominal = 916500000
+	profiles := map[string]radio.Profile{
+		"pairing":    {Frequency: 868350000, DataRate: 1200, TransmitPower: -10, SetPower: true},
+		"long-range": {DataRate: 1200, TransmitPower: 20, SetPower: true},
+		"frequency":  {Frequency: 433920000},
+	}
+	type settings struct {
+		freq  uint32
+		rate  uint32
+		power int
+	}
+	cases := []struct {
+		name    string
+		profile string
+		power   bool
+		want    settings
+		err     error
+	}{
+		{"all settings", "pairing", true, settings{868350000, 1200, -10}, nil},
+		{"unchanged frequency", "long-range", true, settings{nominal, 1200, 20}, nil},
+		{"frequency only", "frequency", true, settings{433920000, 4800, 10}, nil},
+		{"unknown", "fast", true, settings{nominal, 4800, 10}, radio.UnknownProfileError("fast")},
+		{"no power control", "pairing", false, settings{nominal, 4800, 0}, radio.ErrNoTransmitPower},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			rate := &rateRadio{Radio: a, rate: 4800}
+			inner := radio.Interface(rate)
+			power := &powerRadio{rateRadio: rateRadio{Radio: a, rate: 4800}, power: 10}
+			if c.power {
+				inner = power
+			}
+			// The settings must be found through wrappers that lack them.
+			r := radio.NewPacketGap(radio.NewSquelch(inner, 10), time.Millisecond)
+			p := radio.NewProfiles(r)
+			for name, prof := range profiles {
+				p.Define(name, prof)
+			}
+			err := p.UseProfile(c.profile)
+			if err != c.err {
+				t.Errorf("error = %v, want %v", err, c.err)
+			}
+			got := settings{freq: a.Frequency()}
+			if c.power {
+				got.rate, got.power = power.rate, power.power
+			} else {
+				got.rate = rate.rate
+			}
+			if got != c.want {
+				t.Errorf("settings = %+v, want %+v", got, c.want)
+			}
+			current := c.profile
+			if err != nil {
+				current = ""
+			}
+			if p.Current() != current {
+				t.Errorf("Current() = %q, want %q", p.Current(), current)
+			}
+		})
+	}
+}
+
+func TestWithProfile(t *testing.T) {
+	_, a, _ := simPair(t)
+	inner := &powerRadio{rateRadio: rateRadio{Radio: a, rate: 4800}, power: 10}
+	p := radio.NewProfiles(radio.NewPacketGap(radio.NewSquelch(inner, 10), time.Millisecond))
+	p.Define("normal", radio.Profile{DataRate: 9600})
+	p.Define("pairing", radio.Profile{Frequency: 868350000, DataRate: 1200, TransmitPower: -10, SetPower: true})
+	if err := p.UseProfile("normal"); err != nil {
+		t.Fatal(err)
+	}
+	err := p.WithProfile("pairing", func() {
+		if f, r, pw := a.Frequency(), inner.rate, inner.power; f != 868350000 || r != 1200 || pw != -10 {
+			t.Errorf("during WithProfile: frequency %d, rate %d, power %d", f, r, pw)
+		}
+		if p.Current() != "pairing" {
+			t.Errorf("Current() = %q during WithProfile", p.Current())
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, r, pw := a.Frequency(), inner.rate, inner.power; f != 916500000 || r != 9600 || pw != 10 {
+		t.Errorf("after WithProfile: frequency %d, rate %d, power %d", f, r, pw)
+	}
+	if p.Current() != "normal" {
+		t.Errorf("Current() = %q after WithProfile", p.Current())
+	}
+}
@@ -2,6 +2,9 @@ package radio
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -34,3 +37,51 @@ func MegaHertz(freq uint32) string {
 	k := (freq % 1000000) / 1000
 	return fmt.Sprintf("%3d.%03d", m, k)
 }
+
+// ParseMegaHertz converts a string denoting a frequency in MegaHertz,
+// such as "916.5" or the output of MegaHertz, into Hertz.
+// At most 6 decimal places are allowed.
+func ParseMegaHertz(s string) (uint32, error) {
+	t := strings.TrimSpace(s)
+	m, k := t, ""
+	if i := strings.IndexByte(t, '.'); i >= 0 {
+		m, k = t[:i], t[i+1:]
+	}
+	if m == "" || len(k) > 6 || !allDigits(m) || !allDigits(k) {
+		return 0, fmt.Errorf("invalid frequency %q", s)
+	}
+	k += strings.Repeat("0", 6-len(k))
+	mhz, err := strconv.ParseUint(m, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid frequency %q", s)
+	}
+	hz, _ := strconv.ParseUint(k, 10, 32)
+	f := mhz*1000000 + hz
+	if f > math.MaxUint32 {
+		return 0, fmt.Errorf("frequency %q out of range", s)
+	}
+	return uint32(f), nil
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FrequencyToRegister converts a frequency in Hertz to the value of a
+// chip's frequency register, for a synthesizer whose frequency step is
+// the crystal frequency divided by 2 to the power of shift.
+// The result is truncated, as most chip datasheets specify.
+func FrequencyToRegister(freq uint32, crystal uint32, shift uint) uint32 {
+	return uint32((uint64(freq) << shift) / uint64(crystal))
+}
+
+// RegisterToFrequency converts the value of a chip's frequency register
+// to a frequency in Hertz, truncated to an integer; see FrequencyToRegister.
+func RegisterToFrequency(reg uint32, crystal uint32, shift uint) uint32 {
+	return uint32((uint64(reg) * uint64(crystal)) >> shift)
+}
@@ -0,0 +1,56 @@
+package radio_test
+
+import (
+	"testing"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/testvectors"
+)
+
+func TestMegaHertz(t *testing.T) {
+	for _, v := range testvectors.MegaHertz {
+		if got := radio.MegaHertz(v.Hertz); got != v.MegaHertz {
+			t.Errorf("MegaHertz(%d) = %q, want %q", v.Hertz, got, v.MegaHertz)
+		}
+	}
+}
+
+func TestParseMegaHertz(t *testing.T) {
+	for _, v := range testvectors.ParseMegaHertz {
+		got, err := radio.ParseMegaHertz(v.Input)
+		switch {
+		case !v.Valid && err == nil:
+			t.Errorf("ParseMegaHertz(%q) = %d, want an error", v.Input, got)
+		case v.Valid && err != nil:
+			t.Errorf("ParseMegaHertz(%q) error: %v", v.Input, err)
+		case v.Valid && got != v.Hertz:
+			t.Errorf("ParseMegaHertz(%q) = %d, want %d", v.Input, got, v.Hertz)
+		}
+	}
+}
+
+func TestFrequencyRegisters(t *testing.T) {
+	for _, v := range testvectors.Registers {
+		if got := radio.FrequencyToRegister(v.Hertz, v.Crystal, v.Shift); got != v.Register {
+			t.Errorf("FrequencyToRegister(%d, %d, %d) = %d, want %d", v.Hertz, v.Crystal, v.Shift, got, v.Register)
+		}
+		if got := radio.RegisterToFrequency(v.Register, v.Crystal, v.Shift); got != v.Tuned {
+			t.Errorf("RegisterToFrequency(%d, %d, %d) = %d, want %d", v.Register, v.Crystal, v.Shift, got, v.Tuned)
+		}
+	}
+}
+
+// Formatting a parsed frequency must give back the parsed value
+// to the kiloHertz.
+func TestMegaHertzRoundTrip(t *testing.T) {
+	for _, v := range testvectors.MegaHertz {
+		got, err := radio.ParseMegaHertz(v.MegaHertz)
+		if err != nil {
+			t.Errorf("ParseMegaHertz(%q) error: %v", v.MegaHertz, err)
+			continue
+		}
+		if want := v.Hertz / 1000 * 1000; got != want {
+			t.Errorf("ParseMegaHertz(%q) = %d, want %d", v.MegaHertz, got, want)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+// Package radiosoak runs long send and receive loops against a radio,
+// tracking error rates, latency drift, and the growth of memory,
+// goroutines, and open file descriptors, so that kernel and driver
+// upgrades can be validated before deployment:
+//
+//	opts := radiosoak.DefaultOptions
+//	opts.Duration = 8 * time.Hour
+//	opts.Progress = func(rep radiosoak.Report) { log.Print(rep.Samples[len(rep.Samples)-1]) }
+//	rep := radiosoak.Run(r, opts)
+//	fmt.Print(rep)
+package radiosoak
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+// Mode selects the operation performed in each iteration.
+type Mode int
+
+// Soak test modes.
+const (
+	// Ping sends a packet with SendAndReceive and expects a reply,
+	// for example from a device that echoes packets back.
+	Ping Mode = iota
+	// Send only transmits.
+	Send
+	// Receive only listens.
+	Receive
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Ping:
+		return "ping"
+	case Send:
+		return "send"
+	case Receive:
+		return "receive"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// Options configures a soak test.
+type Options struct {
+	Mode Mode
+	// Duration is how long the test runs.
+	Duration time.Duration
+	// Interval is the time between the starts of successive iterations.
+	// Zero runs them back to back.
+	Interval time.Duration
+	// Timeout bounds each receive.
+	Timeout time.Duration
+	// Payload returns the packet to send in the given iteration.
+	// If nil, an 8-byte sequence number is sent.
+	Payload func(seq uint64) []byte
+	// Window is the interval at which resource usage and latency are sampled.
+	Window time.Duration
+	// Progress, if not nil, is called with the report so far after each sample.
+	Progress func(Report)
+	// Stop, if not nil, ends the test early when it is closed.
+	Stop <-chan struct{}
+}
+
+// DefaultOptions runs a one-hour ping test, sampling every minute.
+var DefaultOptions = Options{
+	Mode:     Ping,
+	Duration: time.Hour,
+	Interval: 100 * time.Millisecond,
+	Timeout:  time.Second,
+	Window:   time.Minute,
+}
+
+// Sample records the state of a test at the end of a window.
+// HeapAlloc is measured after a garbage collection, so that it reflects
+// memory still in use rather than garbage awaiting collection.
+// FDs is -1 if the number of open file descriptors cannot be determined.
+type Sample struct {
+	Time       time.Time
+	Operations int
+	Errors     int
+	Timeouts   int
+	Latency    time.Duration
+	HeapAlloc  uint64
+	Goroutines int
+	FDs        int
+}
+
+func (s Sample) String() string {
+	return fmt.Sprintf("%s ops %d errors %d timeouts %d latency %v heap %d goroutines %d fds %d",
+		s.Time.Format(time.RFC3339), s.Operations, s.Errors, s.Timeouts, s.Latency, s.HeapAlloc, s.Goroutines, s.FDs)
+}
+
+// Report summarizes a soak test.
+// Latency covers successful operations only; in Ping mode, replies
+// that do not match the packet sent count as errors.
+// Samples hold per-window figures, the first taken before the test begins.
+type Report struct {
+	Mode       Mode
+	Start      time.Time
+	End        time.Time
+	Operations int
+	Successes  int
+	Errors     int
+	Timeouts   int
+	LastError  string
+	Latency    radio.Timing
+	Samples    []Sample
+}
+
+// ErrorRate returns the fraction of operations that failed with an error.
+func (rep Report) ErrorRate() float64 {
+	if rep.Operations == 0 {
+		return 0
+	}
+	return float64(rep.Errors) / float64(rep.Operations)
+}
+
+// TimeoutRate returns the fraction of operations that timed out.
+func (rep Report) TimeoutRate() float64 {
+	if rep.Operations == 0 {
+		return 0
+	}
+	return float64(rep.Timeouts) / float64(rep.Operations)
+}
+
+// LatencyDrift returns the change in mean latency
+// from the first window with successes to the last.
+func (rep Report) LatencyDrift() time.Duration {
+	var first, last time.Duration
+	for _, s := range rep.Samples {
+		if s.Latency == 0 {
+			continue
+		}
+		if first == 0 {
+			first = s.Latency
+		}
+		last = s.Latency
+	}
+	return last - first
+}
+
+// HeapGrowth returns the change in heap allocation over the test.
+func (rep Report) HeapGrowth() int64 {
+	first, last, ok := rep.ends()
+	if !ok {
+		return 0
+	}
+	return int64(last.HeapAlloc) - int64(first.HeapAlloc)
+}
+
+// GoroutineGrowth returns the change in the number of goroutines over the test.
+func (rep Report) GoroutineGrowth() int {
+	first, last, ok := rep.ends()
+	if !ok {
+		return 0
+	}
+	return last.Goroutines - first.Goroutines
+}
+
+// FDGrowth returns the change in the number of open file descriptors
+// over the test, or 0 if it could not be determined.
+func (rep Report) FDGrowth() int {
+	first, last, ok := rep.ends()
+	if !ok || first.FDs < 0 || last.FDs < 0 {
+		return 0
+	}
+	return last.FDs - first.FDs
+}
+
+func (rep Report) ends() (Sample, Sample, bool) {
+	n := len(rep.Samples)
+	if n < 2 {
+		return Sample{}, Sample{}, false
+	}
+	return rep.Samples[0], rep.Samples[n-1], true
+}
+
+func (rep Report) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s soak test: %v (%s to %s)\n", rep.Mode, rep.End.Sub(rep.Start).Round(time.Second),
+		rep.Start.Format(time.RFC3339), rep.End.Format(time.RFC3339))
+	fmt.Fprintf(&buf, "operations %d, successes %d, errors %d (%.3f%%), timeouts %d (%.3f%%)\n",
+		rep.Operations, rep.Successes, rep.Errors, 100*rep.ErrorRate(), rep.Timeouts, 100*rep.TimeoutRate())
+	if rep.LastError != "" {
+		fmt.Fprintf(&buf, "last error: %s\n", rep.LastError)
+	}
+	l := rep.Latency
+	fmt.Fprintf(&buf, "latency mean %v, min %v, p50 %v, p99 %v, max %v, drift %v\n",
+		l.Mean(), l.Min, l.Histogram.Quantile(0.5), l.Histogram.Quantile(0.99), l.Max, rep.LatencyDrift())
+	fmt.Fprintf(&buf, "growth: heap %d bytes, goroutines %d, fds %d\n",
+		rep.HeapGrowth(), rep.GoroutineGrowth(), rep.FDGrowth())
+	return buf.String()
+}
+
+// Run runs a soak test against r, which must already be initialized,
+// and returns its report. Errors are recorded and cleared so that the
+// test continues.
+func Run(r radio.Interface, opts Options) Report {
+	if opts.Payload == nil {
+		opts.Payload = sequencePayload
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultOptions.Window
+	}
+	rep := Report{Mode: opts.Mode, Start: time.Now()}
+	rep.Samples = append(rep.Samples, sample(rep, radio.Timing{}))
+	deadline := rep.Start.Add(opts.Duration)
+	nextSample := rep.Start.Add(opts.Window)
+	var window radio.Timing
+	for seq := uint64(0); time.Now().Before(deadline); seq++ {
+		if stopped(opts.Stop) {
+			break
+		}
+		begin := time.Now()
+		if d, ok := iterate(r, opts, seq, &rep); ok {
+			rep.Latency.Add(d)
+			window.Add(d)
+		}
+		if time.Now().After(nextSample) {
+			rep.Samples = append(rep.Samples, sample(rep, window))
+			window = radio.Timing{}
+			nextSample = nextSample.Add(opts.Window)
+			if opts.Progress != nil {
+				opts.Progress(rep)
+			}
+		}
+		if wait := opts.Interval - time.Since(begin); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+	rep.End = time.Now()
+	rep.Samples = append(rep.Samples, sample(rep, window))
+	return rep
+}
+
+// iterate performs one operation and returns its latency if it succeeded.
+func iterate(r radio.Interface, opts Options, seq uint64, rep *Report) (time.Duration, bool) {
+	rep.Operations++
+	start := time.Now()
+	var data, reply []byte
+	switch opts.Mode {
+	case Ping:
+		data = opts.Payload(seq)
+		reply, _ = r.SendAndReceive(data, opts.Timeout)
+	case Send:
+		r.Send(opts.Payload(seq))
+	case Receive:
+		reply, _ = r.Receive(opts.Timeout)
+	}
+	elapsed := time.Since(start)
+	err := r.Error()
+	r.SetError(nil)
+	switch {
+	case radio.IsTimeout(err) || (err == nil && opts.Mode != Send && reply == nil):
+		rep.Timeouts++
+		return 0, false
+	case err != nil:
+		rep.Errors++
+		rep.LastError = err.Error()
+		return 0, false
+	case opts.Mode == Ping && !bytes.Equal(reply, data):
+		rep.Errors++
+		rep.LastError = fmt.Sprintf("reply % X does not match packet % X", reply, data)
+		return 0, false
+	}
+	rep.Successes++
+	return elapsed, true
+}
+
+func stopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func sequencePayload(seq uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, seq)
+	return buf
+}
+
+func sample(rep Report, window radio.Timing) Sample {
+	var mem runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&mem)
+	return Sample{
+		Time:       time.Now(),
+		Operations: rep.Operations,
+		Errors:     rep.Errors,
+		Timeouts:   rep.Timeouts,
+		Latency:    window.Mean(),
+		HeapAlloc:  mem.HeapAlloc,
+		Goroutines: runtime.NumGoroutine(),
+		FDs:        openFDs(),
+	}
+}
+
+// openFDs returns the number of open file descriptors, or -1 if unknown.
+func openFDs() int {
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(fds)
+}
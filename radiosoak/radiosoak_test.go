@@ -0,0 +1,102 @@
+package radiosoak
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+var garbage [][]byte
+
+func TestSampleHeap(t *testing.T) {
+	before := sample(Report{}, radio.Timing{})
+	for i := 0; i < 1024; i++ {
+		garbage = append(garbage, make([]byte, 32<<10))
+	}
+	garbage = nil
+	after := sample(Report{}, radio.Timing{})
+	if growth := int64(after.HeapAlloc) - int64(before.HeapAlloc); growth > 8<<20 {
+		t.Errorf("heap grew by %d bytes of garbage", growth)
+	}
+}
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		mode      Mode
+		successes bool
+		timeouts  bool
+	}{
+		{Send, true, false},
+		{Receive, false, true},
+		{Ping, false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.mode.String(), func(t *testing.T) {
+			r := sim.NewMedium().NewRadio("r")
+			r.Init(916500000)
+			rep := Run(r, Options{
+				Mode:     c.mode,
+				Duration: 50 * time.Millisecond,
+				Interval: time.Millisecond,
+				Timeout:  5 * time.Millisecond,
+				Window:   20 * time.Millisecond,
+			})
+			if rep.Operations == 0 {
+				t.Fatal("no operations were performed")
+			}
+			if got := rep.Successes == rep.Operations; got != c.successes {
+				t.Errorf("%d of %d operations succeeded", rep.Successes, rep.Operations)
+			}
+			if got := rep.Timeouts == rep.Operations; got != c.timeouts {
+				t.Errorf("%d of %d operations timed out", rep.Timeouts, rep.Operations)
+			}
+			if rep.Errors != 0 {
+				t.Errorf("%d errors, last %q", rep.Errors, rep.LastError)
+			}
+			if len(rep.Samples) < 3 {
+				t.Errorf("took %d samples, want at least 3", len(rep.Samples))
+			}
+		})
+	}
+}
+
+// noisyRadio is a simulated radio whose receives fail with err.
+type noisyRadio struct {
+	*sim.Radio
+	err error
+}
+
+func (r *noisyRadio) Receive(timeout time.Duration) ([]byte, int) {
+	r.SetError(r.err)
+	return nil, 0
+}
+
+func TestIterateTimeouts(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		timeouts int
+		errors   int
+	}{
+		{"interrupt timeout", radio.InterruptTimeoutError{Pin: 24, Timeout: time.Millisecond}, 1, 0},
+		{"wrapped timeout", fmt.Errorf("receive: %w", radio.ReceiveTimeoutError{}), 1, 0},
+		{"failure", errors.New("spi failure"), 0, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := &noisyRadio{Radio: sim.NewMedium().NewRadio("r"), err: c.err}
+			var rep Report
+			iterate(r, Options{Mode: Receive, Timeout: time.Millisecond}, 0, &rep)
+			if rep.Timeouts != c.timeouts || rep.Errors != c.errors {
+				t.Errorf("timeouts %d errors %d, want timeouts %d errors %d", rep.Timeouts, rep.Errors, c.timeouts, c.errors)
+			}
+			if r.Error() != nil {
+				t.Errorf("error state not cleared: %v", r.Error())
+			}
+		})
+	}
+}
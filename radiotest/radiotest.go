@@ -0,0 +1,217 @@
+// Package radiotest provides a conformance suite for implementations
+// of radio.Interface, so that every chip driver exhibits the same
+// observable behavior. A driver's tests call it with a function that
+// opens a fresh radio:
+//
+//	func TestConformance(t *testing.T) {
+//		radiotest.TestInterface(t, func() radio.Interface { return rfm69.Open() })
+//	}
+package radiotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+// Frequency is the frequency used by the suite.
+var Frequency uint32 = 916500000
+
+// Tolerance is the largest acceptable difference, in Hertz, between a
+// requested frequency and the one reported by the radio, to allow for
+// the resolution of the chip's synthesizer.
+var Tolerance uint32 = 1000
+
+// Slack is the time a timed operation may overrun its timeout.
+var Slack = 250 * time.Millisecond
+
+// TestInterface runs the conformance suite against radios returned by open.
+// Each subtest opens and closes its own radio.
+func TestInterface(t *testing.T, open func() radio.Interface) {
+	tests := []struct {
+		name string
+		test func(*testing.T, radio.Interface)
+	}{
+		{"Init", testInit},
+		{"SetFrequency", testSetFrequency},
+		{"Error", testError},
+		{"Identity", testIdentity},
+		{"Send", testSend},
+		{"ReceiveTimeout", testReceiveTimeout},
+		{"SendAndReceiveTimeout", testSendAndReceiveTimeout},
+		{"Reset", testReset},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := open()
+			if r.Error() != nil {
+				t.Fatalf("open: %v", r.Error())
+			}
+			defer r.Close()
+			tt.test(t, r)
+		})
+	}
+}
+
+// TestPair checks that a packet sent by one radio is received by another.
+// The radios must be within range of each other.
+func TestPair(t *testing.T, sender, receiver radio.Interface) {
+	sender.Init(Frequency)
+	receiver.Init(Frequency)
+	mustNotFail(t, sender, "Init")
+	mustNotFail(t, receiver, "Init")
+	packet := []byte{0x55, 0xAA, 0x01, 0x02, 0x03, 0x04}
+	got := make(chan []byte, 1)
+	go func() {
+		data, _ := receiver.Receive(2 * time.Second)
+		got <- data
+	}()
+	time.Sleep(50 * time.Millisecond)
+	sender.Send(packet)
+	mustNotFail(t, sender, "Send")
+	data := <-got
+	if data == nil {
+		t.Fatalf("no packet received: %v", receiver.Error())
+	}
+	if string(data) != string(packet) {
+		t.Errorf("received % X, want % X", data, packet)
+	}
+}
+
+func mustNotFail(t *testing.T, r radio.Interface, op string) {
+	t.Helper()
+	if err := r.Error(); err != nil {
+		t.Fatalf("%s: %v", op, err)
+	}
+}
+
+func near(a, b uint32) bool {
+	if a > b {
+		a, b = b, a
+	}
+	return b-a <= Tolerance
+}
+
+func testInit(t *testing.T, r radio.Interface) {
+	r.Init(Frequency)
+	mustNotFail(t, r, "Init")
+	if f := r.Frequency(); !near(f, Frequency) {
+		t.Errorf("Frequency() = %d after Init(%d)", f, Frequency)
+	}
+}
+
+func testSetFrequency(t *testing.T, r radio.Interface) {
+	r.Init(Frequency)
+	mustNotFail(t, r, "Init")
+	for _, f := range []uint32{Frequency - 500000, Frequency + 500000, Frequency} {
+		r.SetFrequency(f)
+		mustNotFail(t, r, "SetFrequency")
+		if got := r.Frequency(); !near(got, f) {
+			t.Errorf("Frequency() = %d after SetFrequency(%d)", got, f)
+		}
+	}
+}
+
+func testError(t *testing.T, r radio.Interface) {
+	r.Init(Frequency)
+	mustNotFail(t, r, "Init")
+	e := errors.New("radiotest")
+	r.SetError(e)
+	if r.Error() != e {
+		t.Errorf("Error() = %v after SetError(%v)", r.Error(), e)
+	}
+	r.SetError(nil)
+	if r.Error() != nil {
+		t.Errorf("Error() = %v after SetError(nil)", r.Error())
+	}
+}
+
+func testIdentity(t *testing.T, r radio.Interface) {
+	if r.Name() == "" {
+		t.Error("Name() is empty")
+	}
+	if r.Device() == "" {
+		t.Error("Device() is empty")
+	}
+	r.Init(Frequency)
+	mustNotFail(t, r, "Init")
+	if r.State() == "" {
+		t.Error("State() is empty")
+	}
+}
+
+func testSend(t *testing.T, r radio.Interface) {
+	r.Init(Frequency)
+	mustNotFail(t, r, "Init")
+	r.Send([]byte{0x55, 0xAA, 0x01, 0x02})
+	mustNotFail(t, r, "Send")
+}
+
+func timed(t *testing.T, timeout time.Duration, f func()) {
+	t.Helper()
+	start := time.Now()
+	f()
+	if elapsed := time.Since(start); elapsed > timeout+Slack {
+		t.Errorf("took %v with timeout %v", elapsed, timeout)
+	}
+}
+
+// checkTimeout checks the outcome of a receive operation that timed out:
+// no data, and either no error or one satisfying radio.IsTimeout,
+// which must not persist once the error state is cleared.
+func checkTimeout(t *testing.T, r radio.Interface, op string, data []byte) {
+	t.Helper()
+	if data != nil {
+		// A packet may legitimately arrive from another transmitter.
+		t.Logf("%s received % X", op, data)
+		r.SetError(nil)
+		return
+	}
+	if err := r.Error(); err != nil && !radio.IsTimeout(err) {
+		t.Errorf("%s timed out with non-timeout error %v", op, err)
+	}
+	r.SetError(nil)
+	if err := r.Error(); err != nil {
+		t.Errorf("Error() = %v after %s timeout and SetError(nil)", err, op)
+	}
+}
+
+func testReceiveTimeout(t *testing.T, r radio.Interface) {
+	r.Init(Frequency)
+	mustNotFail(t, r, "Init")
+	const timeout = 100 * time.Millisecond
+	var data []byte
+	timed(t, timeout, func() {
+		data, _ = r.Receive(timeout)
+	})
+	checkTimeout(t, r, "Receive", data)
+	r.Send([]byte{0x55})
+	mustNotFail(t, r, "Send after Receive timeout")
+}
+
+func testSendAndReceiveTimeout(t *testing.T, r radio.Interface) {
+	r.Init(Frequency)
+	mustNotFail(t, r, "Init")
+	const timeout = 100 * time.Millisecond
+	var data []byte
+	timed(t, timeout, func() {
+		data, _ = r.SendAndReceive([]byte{0x55, 0xAA}, timeout)
+	})
+	checkTimeout(t, r, "SendAndReceive", data)
+	r.Send([]byte{0x55})
+	mustNotFail(t, r, "Send after SendAndReceive timeout")
+}
+
+func testReset(t *testing.T, r radio.Interface) {
+	r.Init(Frequency)
+	mustNotFail(t, r, "Init")
+	r.Reset()
+	mustNotFail(t, r, "Reset")
+	r.Init(Frequency)
+	mustNotFail(t, r, "Init after Reset")
+	r.Send([]byte{0x55})
+	mustNotFail(t, r, "Send after Reset")
+}
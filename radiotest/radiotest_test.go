@@ -0,0 +1,60 @@
+package radiotest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/radiotest"
+	"github.com/ecc1/radio/sim"
+)
+
+// TestSimInterface runs the suite against the simulated radio,
+// which serves as its reference implementation.
+func TestSimInterface(t *testing.T) {
+	m := sim.NewMedium()
+	radiotest.TestInterface(t, func() radio.Interface { return m.NewRadio("sim") })
+}
+
+// timeoutRadio is a simulated radio that reports receive timeouts
+// in its error state, as the hardware drivers do.
+type timeoutRadio struct{ *sim.Radio }
+
+func (r timeoutRadio) Receive(timeout time.Duration) ([]byte, int) {
+	data, rssi := r.Radio.Receive(timeout)
+	if data == nil && r.Error() == nil {
+		r.SetError(radio.ReceiveTimeoutError{Timeout: timeout})
+	}
+	return data, rssi
+}
+
+func (r timeoutRadio) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	r.Send(data)
+	if r.Error() != nil {
+		return nil, 0
+	}
+	return r.Receive(timeout)
+}
+
+func TestTimeoutInterface(t *testing.T) {
+	m := sim.NewMedium()
+	radiotest.TestInterface(t, func() radio.Interface { return timeoutRadio{m.NewRadio("sim")} })
+}
+
+func TestSimPair(t *testing.T) {
+	cases := []struct {
+		name string
+		link sim.Link
+	}{
+		{"default", sim.DefaultLink},
+		{"weak", sim.Link{RSSI: -100}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sim.NewMedium()
+			a, b := m.NewRadio("a"), m.NewRadio("b")
+			m.SetLinks(a, b, c.link)
+			radiotest.TestPair(t, a, b)
+		})
+	}
+}
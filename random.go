@@ -0,0 +1,16 @@
+package radio
+
+import (
+	"math/rand"
+	"time"
+)
+
+// NewSource returns src, or a source seeded from the current time if src is nil.
+// Components that make random choices, such as CSMA backoff, accept an
+// optional rand.Source so that tests can be reproducible.
+func NewSource(src rand.Source) rand.Source {
+	if src != nil {
+		return src
+	}
+	return rand.NewSource(time.Now().UnixNano())
+}
@@ -0,0 +1,201 @@
+package radio
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// tokenBucket refills at rate tokens per second, up to one second's worth.
+// A zero rate means no limit.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) refill(t time.Time) {
+	if b.last.IsZero() {
+		b.tokens = b.rate
+	} else {
+		b.tokens += t.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+	}
+	b.last = t
+}
+
+// delay returns how long to wait at time t until n tokens are available.
+func (b *tokenBucket) delay(t time.Time, n float64) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+	b.refill(t)
+	if n > b.rate {
+		// Let a request larger than the bucket through once it is full,
+		// leaving the bucket in debt.
+		n = b.rate
+	}
+	if b.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+}
+
+// take removes n tokens, which may leave the bucket in debt.
+func (b *tokenBucket) take(n float64) {
+	if b.rate > 0 {
+		b.tokens -= n
+	}
+}
+
+// allow takes one token if one is available at time t.
+func (b *tokenBucket) allow(t time.Time) bool {
+	if b.delay(t, 1) > 0 {
+		return false
+	}
+	b.take(1)
+	return true
+}
+
+// Airtime returns the time needed to transmit a packet of n bytes,
+// plus overhead bytes of preamble, sync word, and so on, at the given
+// data rate in bits per second.
+func Airtime(n int, overhead int, bitsPerSecond uint32) time.Duration {
+	if bitsPerSecond == 0 {
+		return 0
+	}
+	bits := int64(n+overhead) * 8
+	return time.Duration(bits * int64(time.Second) / int64(bitsPerSecond))
+}
+
+// ErrRateLimited indicates that a packet was not sent because it would
+// exceed the configured transmit budget.
+var ErrRateLimited = errors.New("transmit rate limit exceeded")
+
+// RateLimitConfig configures a RateLimiter.
+type RateLimitConfig struct {
+	// PacketsPerSecond limits the packet rate. Zero means no limit.
+	PacketsPerSecond float64
+	// Airtime limits the transmit time per second of elapsed time,
+	// for example 10ms for a 1% duty cycle. Zero means no limit.
+	Airtime time.Duration
+	// DataRate is the data rate in bits per second used to compute airtime.
+	// If zero, it is obtained from the radio if it implements DataRater.
+	DataRate uint32
+	// Overhead is the number of bytes sent in addition to each payload.
+	Overhead int
+	// Block waits until the budget allows a packet to be sent,
+	// instead of setting the error state to ErrRateLimited.
+	Block bool
+	// Clock provides the time. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// RateLimitStats records the transmissions handled by a RateLimiter.
+type RateLimitStats struct {
+	Packets  int
+	Airtime  time.Duration
+	Rejected int
+	Delayed  time.Duration
+}
+
+// RateLimiter wraps a radio, limiting its packet rate and airtime.
+type RateLimiter struct {
+	Interface
+	config RateLimitConfig
+	clocked
+
+	mu      sync.Mutex
+	packets tokenBucket
+	airtime tokenBucket
+	stats   RateLimitStats
+}
+
+// NewRateLimiter returns a RateLimiter for r with the given configuration.
+func NewRateLimiter(r Interface, config RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		Interface: r,
+		config:    config,
+		clocked:   clocked{config.Clock},
+		packets:   tokenBucket{rate: config.PacketsPerSecond},
+		airtime:   tokenBucket{rate: config.Airtime.Seconds()},
+	}
+}
+
+// Unwrap returns the radio wrapped by l.
+func (l *RateLimiter) Unwrap() Interface {
+	return l.Interface
+}
+
+// Stats returns the cumulative transmit statistics.
+func (l *RateLimiter) Stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+func (l *RateLimiter) airtimeOf(data []byte) time.Duration {
+	rate := l.config.DataRate
+	if rate == 0 {
+		if d, ok := Find(l.Interface, isDataRater).(DataRater); ok {
+			rate = d.DataRate()
+		}
+	}
+	return Airtime(len(data), l.config.Overhead, rate)
+}
+
+func isDataRater(r Interface) bool {
+	_, ok := r.(DataRater)
+	return ok
+}
+
+// admit waits for or rejects a packet with the given airtime,
+// and reports whether it may be sent.
+func (l *RateLimiter) admit(air time.Duration) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for {
+		t := l.now()
+		wait := l.packets.delay(t, 1)
+		if d := l.airtime.delay(t, air.Seconds()); d > wait {
+			wait = d
+		}
+		if wait == 0 {
+			break
+		}
+		if !l.config.Block {
+			l.stats.Rejected++
+			return false
+		}
+		l.stats.Delayed += wait
+		l.mu.Unlock()
+		l.sleep(wait)
+		l.mu.Lock()
+	}
+	l.packets.take(1)
+	l.airtime.take(air.Seconds())
+	l.stats.Packets++
+	l.stats.Airtime += air
+	return true
+}
+
+// Send transmits data if the transmit budget allows.
+func (l *RateLimiter) Send(data []byte) {
+	if !l.admit(l.airtimeOf(data)) {
+		l.SetError(ErrRateLimited)
+		return
+	}
+	l.Interface.Send(data)
+}
+
+// SendAndReceive transmits data if the transmit budget allows,
+// then receives a reply with the given timeout.
+func (l *RateLimiter) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	if !l.admit(l.airtimeOf(data)) {
+		l.SetError(ErrRateLimited)
+		return nil, 0
+	}
+	return l.Interface.SendAndReceive(data, timeout)
+}
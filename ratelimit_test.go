@@ -0,0 +1,164 @@
+package radio_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+func TestAirtime(t *testing.T) {
+	cases := []struct {
+		n, overhead int
+		rate        uint32
+		want        time.Duration
+	}{
+		{10, 0, 8000, 10 * time.Millisecond},
+		{8, 2, 8000, 10 * time.Millisecond},
+		{0, 0, 8000, 0},
+		{100, 10, 0, 0},
+		{125, 0, 1000000, time.Millisecond},
+	}
+	for _, c := range cases {
+		if got := radio.Airtime(c.n, c.overhead, c.rate); got != c.want {
+			t.Errorf("Airtime(%d, %d, %d) = %v, want %v", c.n, c.overhead, c.rate, got, c.want)
+		}
+	}
+}
+
+type rateStep struct {
+	advance time.Duration
+	size    int
+	sent    bool
+}
+
+func TestRateLimiter(t *testing.T) {
+	cases := []struct {
+		name   string
+		config radio.RateLimitConfig
+		rate   uint32
+		steps  []rateStep
+		stats  radio.RateLimitStats
+	}{
+		{
+			name:   "no limit",
+			config: radio.RateLimitConfig{},
+			steps:  []rateStep{{0, 10, true}, {0, 10, true}, {0, 10, true}},
+			stats:  radio.RateLimitStats{Packets: 3},
+		},
+		{
+			name:   "packet rate",
+			config: radio.RateLimitConfig{PacketsPerSecond: 2},
+			steps: []rateStep{
+				{0, 1, true}, {0, 1, true}, {0, 1, false},
+				{500 * time.Millisecond, 1, true}, {0, 1, false},
+			},
+			stats: radio.RateLimitStats{Packets: 3, Rejected: 2},
+		},
+		{
+			name:   "airtime",
+			config: radio.RateLimitConfig{Airtime: 10 * time.Millisecond, DataRate: 8000},
+			steps:  []rateStep{{0, 10, true}, {0, 10, false}, {time.Second, 10, true}},
+			stats:  radio.RateLimitStats{Packets: 2, Airtime: 20 * time.Millisecond, Rejected: 1},
+		},
+		{
+			name:   "data rate from chain",
+			config: radio.RateLimitConfig{Airtime: 10 * time.Millisecond, Overhead: 2},
+			rate:   8000,
+			steps:  []rateStep{{0, 8, true}, {0, 8, false}, {500 * time.Millisecond, 3, true}},
+			stats:  radio.RateLimitStats{Packets: 2, Airtime: 15 * time.Millisecond, Rejected: 1},
+		},
+		{
+			name:   "configured data rate wins",
+			config: radio.RateLimitConfig{Airtime: 10 * time.Millisecond, DataRate: 16000},
+			rate:   8000,
+			steps:  []rateStep{{0, 10, true}, {0, 10, true}, {0, 10, false}},
+			stats:  radio.RateLimitStats{Packets: 2, Airtime: 10 * time.Millisecond, Rejected: 1},
+		},
+		{
+			name:   "unknown data rate",
+			config: radio.RateLimitConfig{Airtime: 10 * time.Millisecond},
+			steps:  []rateStep{{0, 100, true}, {0, 100, true}},
+			stats:  radio.RateLimitStats{Packets: 2},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			_, a, _ := simPair(t)
+			clock := radio.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			c.config.Clock = clock
+			l := radio.NewRateLimiter(&rateRadio{Radio: a, rate: c.rate}, c.config)
+			for i, s := range c.steps {
+				clock.Advance(s.advance)
+				l.Send(make([]byte, s.size))
+				err := l.Error()
+				l.SetError(nil)
+				switch {
+				case s.sent && err != nil:
+					t.Errorf("step %d: Send failed: %v", i, err)
+				case !s.sent && !errors.Is(err, radio.ErrRateLimited):
+					t.Errorf("step %d: error = %v, want %v", i, err, radio.ErrRateLimited)
+				}
+			}
+			if got := l.Stats(); got != c.stats {
+				t.Errorf("stats = %+v, want %+v", got, c.stats)
+			}
+		})
+	}
+}
+
+func TestRateLimiterBlock(t *testing.T) {
+	cases := []struct {
+		name   string
+		config radio.RateLimitConfig
+		budget int
+		wait   time.Duration
+	}{
+		{"packet rate", radio.RateLimitConfig{PacketsPerSecond: 4}, 4, 250 * time.Millisecond},
+		{"airtime", radio.RateLimitConfig{Airtime: 10 * time.Millisecond, DataRate: 8000}, 1, time.Second},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			_, a, _ := simPair(t)
+			clock := radio.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			c.config.Clock = clock
+			c.config.Block = true
+			l := radio.NewRateLimiter(a, c.config)
+			data := make([]byte, 10)
+			for i := 0; i < c.budget; i++ {
+				l.Send(data)
+			}
+			if l.Stats().Delayed != 0 {
+				t.Fatal("Send blocked within the budget")
+			}
+			done := make(chan struct{})
+			go func() {
+				l.Send(data)
+				close(done)
+			}()
+			waitFor(t, func() bool { return clock.Waiters() == 1 })
+			select {
+			case <-done:
+				t.Fatal("Send did not block")
+			default:
+			}
+			clock.Advance(c.wait)
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Send still blocked after the budget refilled")
+			}
+			if err := l.Error(); err != nil {
+				t.Errorf("Send failed: %v", err)
+			}
+			if got := l.Stats().Delayed; got != c.wait {
+				t.Errorf("delayed %v, want %v", got, c.wait)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package radio
+
+import (
+	"runtime"
+)
+
+// SetRealtime locks the calling goroutine to its OS thread and switches
+// that thread to the SCHED_FIFO policy with the given priority (1-99),
+// so a receive loop is not starved by other work on a busy system.
+// Raising the priority requires CAP_SYS_NICE or a suitable RLIMIT_RTPRIO;
+// if that fails, the thread remains locked and the error is returned.
+// The returned function restores normal scheduling and unlocks the thread;
+// it must be called from the same goroutine.
+func SetRealtime(priority int) (restore func(), err error) {
+	runtime.LockOSThread()
+	err = setScheduler(schedFIFO, priority)
+	restore = func() {
+		if err == nil {
+			_ = setScheduler(schedOther, 0)
+		}
+		runtime.UnlockOSThread()
+	}
+	return restore, err
+}
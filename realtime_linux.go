@@ -0,0 +1,30 @@
+//go:build linux
+// +build linux
+
+package radio
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Scheduling policies from <sched.h>.
+const (
+	schedOther = 0
+	schedFIFO  = 1
+)
+
+type schedParam struct {
+	priority int32
+}
+
+func setScheduler(policy int, priority int) error {
+	param := schedParam{priority: int32(priority)}
+	// A pid of 0 refers to the calling thread.
+	_, _, errno := unix.Syscall(unix.SYS_SCHED_SETSCHEDULER, 0, uintptr(policy), uintptr(unsafe.Pointer(&param)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package radio
+
+const (
+	schedOther = 0
+	schedFIFO  = 1
+)
+
+func setScheduler(policy int, priority int) error {
+	return ErrUnsupportedPlatform
+}
@@ -0,0 +1,62 @@
+package radio
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReceiveTimeoutError indicates that a receive operation timed out.
+// Started is true if a packet began to arrive but was never completed,
+// in which case Partial holds the data received so far.
+type ReceiveTimeoutError struct {
+	Timeout time.Duration
+	Started bool
+	Partial []byte
+}
+
+func (e ReceiveTimeoutError) Error() string {
+	if e.Started {
+		return fmt.Sprintf("incomplete packet (%d bytes) after %v", len(e.Partial), e.Timeout)
+	}
+	return fmt.Sprintf("no packet received after %v", e.Timeout)
+}
+
+// IsTimeout reports whether err is a receive or interrupt timeout,
+// which radios report through the error state when no packet arrives.
+// Loops that receive repeatedly can clear such errors and continue,
+// but should treat any other error as a failure of the radio.
+func IsTimeout(err error) bool {
+	var rt ReceiveTimeoutError
+	var it InterruptTimeoutError
+	return errors.As(err, &rt) || errors.As(err, &it)
+}
+
+// FullReceiver is implemented by radios that can distinguish a receive
+// timeout with no activity from one in which a packet never completed.
+// ReceiveFull returns a ReceiveTimeoutError in both cases.
+type FullReceiver interface {
+	ReceiveFull(time.Duration) ([]byte, int, error)
+}
+
+// ReceiveFull receives a packet with the given timeout. If r does not
+// implement FullReceiver, a timeout is always reported as a
+// ReceiveTimeoutError with no packet started, including one that r
+// reports in its error state as an InterruptTimeoutError.
+func ReceiveFull(r Interface, timeout time.Duration) ([]byte, int, error) {
+	if f, ok := r.(FullReceiver); ok {
+		return f.ReceiveFull(timeout)
+	}
+	data, rssi := r.Receive(timeout)
+	if err := r.Error(); err != nil {
+		var rt ReceiveTimeoutError
+		if !errors.As(err, &rt) && IsTimeout(err) {
+			err = ReceiveTimeoutError{Timeout: timeout}
+		}
+		return nil, rssi, err
+	}
+	if data == nil {
+		return nil, rssi, ReceiveTimeoutError{Timeout: timeout}
+	}
+	return data, rssi, nil
+}
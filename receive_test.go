@@ -0,0 +1,96 @@
+package radio_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+// fullRadio implements FullReceiver, reporting a partial packet.
+type fullRadio struct{ *sim.Radio }
+
+func (r fullRadio) ReceiveFull(timeout time.Duration) ([]byte, int, error) {
+	return nil, 0, radio.ReceiveTimeoutError{Timeout: timeout, Started: true, Partial: []byte{1}}
+}
+
+func TestReceiveFull(t *testing.T) {
+	const timeout = 5 * time.Millisecond
+	failure := errors.New("spi failure")
+	partial := radio.ReceiveTimeoutError{Timeout: time.Second, Started: true, Partial: []byte{1, 2}}
+	cases := []struct {
+		name  string
+		radio func(r *sim.Radio) radio.Interface
+		send  bool
+		data  []byte
+		err   error
+	}{
+		{"packet", func(r *sim.Radio) radio.Interface { return r }, true, []byte{7}, nil},
+		{"silent timeout", func(r *sim.Radio) radio.Interface { return r }, false, nil, radio.ReceiveTimeoutError{Timeout: timeout}},
+		{"interrupt timeout", func(r *sim.Radio) radio.Interface {
+			return &noisyRadio{Radio: r, err: radio.InterruptTimeoutError{Pin: 24, Timeout: time.Second}}
+		}, false, nil, radio.ReceiveTimeoutError{Timeout: timeout}},
+		{"receive timeout kept", func(r *sim.Radio) radio.Interface {
+			return &noisyRadio{Radio: r, err: partial}
+		}, false, nil, partial},
+		{"failure", func(r *sim.Radio) radio.Interface {
+			return &noisyRadio{Radio: r, err: failure}
+		}, false, nil, failure},
+		{"full receiver", func(r *sim.Radio) radio.Interface { return fullRadio{r} }, false, nil,
+			radio.ReceiveTimeoutError{Timeout: timeout, Started: true, Partial: []byte{1}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, b := simPair(t)
+			r := c.radio(b)
+			if c.send {
+				go func() {
+					for b.State() != "Receive" {
+						time.Sleep(time.Millisecond)
+					}
+					a.Send(c.data)
+				}()
+			}
+			to := timeout
+			if c.send {
+				to = time.Second
+			}
+			data, _, err := radio.ReceiveFull(r, to)
+			if !reflect.DeepEqual(data, c.data) {
+				t.Errorf("data = %v, want %v", data, c.data)
+			}
+			if !reflect.DeepEqual(err, c.err) {
+				t.Errorf("error = %#v, want %#v", err, c.err)
+			}
+		})
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	interrupt := radio.InterruptTimeoutError{Pin: 24, Timeout: 500 * time.Millisecond}
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("spi failure"), false},
+		{radio.ErrWaitCanceled, false},
+		{interrupt, true},
+		{radio.ReceiveTimeoutError{Timeout: time.Second}, true},
+		{fmt.Errorf("receive: %w", interrupt), true},
+		{fmt.Errorf("receive: %w", radio.ReceiveTimeoutError{}), true},
+	}
+	for _, c := range cases {
+		if got := radio.IsTimeout(c.err); got != c.want {
+			t.Errorf("IsTimeout(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+	// Drivers that matched the gpio package's message keep working.
+	if got, want := interrupt.Error(), "gpio24.Wait timeout after 500ms"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
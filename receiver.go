@@ -0,0 +1,247 @@
+package radio
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Packet is a packet received over the air.
+type Packet struct {
+	Data      []byte
+	RSSI      int
+	Frequency uint32
+	Time      time.Time
+}
+
+// OverflowPolicy selects what a Receiver does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// DropOldest discards the oldest queued packet to make room.
+	DropOldest OverflowPolicy = iota
+	// DropNewest discards the packet that was just received.
+	DropNewest
+	// Block waits for the consumer to make room.
+	Block
+)
+
+// ReceiverOptions configures a Receiver.
+type ReceiverOptions struct {
+	// QueueDepth is the number of packets buffered for the consumer.
+	QueueDepth int
+	// Overflow is the policy applied when the queue is full.
+	Overflow OverflowPolicy
+	// Timeout is passed to each call of the radio's Receive method.
+	Timeout time.Duration
+	// Priority, if positive, runs the receive loop with real-time
+	// scheduling at this priority (see SetRealtime).
+	Priority int
+	// Clock provides the time. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// DefaultReceiverOptions are used for zero fields of ReceiverOptions.
+var DefaultReceiverOptions = ReceiverOptions{
+	QueueDepth: 16,
+	Overflow:   DropOldest,
+	Timeout:    time.Second,
+}
+
+// Receiver receives packets from a radio in a background goroutine
+// and delivers them on a bounded channel.
+type Receiver struct {
+	radio   Interface
+	opts    ReceiverOptions
+	packets chan Packet
+	done    chan struct{}
+	stop    sync.Once
+	wg      sync.WaitGroup
+	clocked
+
+	mu      sync.Mutex
+	dropped int
+	latency Timing
+	err     error
+}
+
+// Backoff bounds used by receive loops when the radio reports
+// errors other than timeouts, so a failed radio does not spin.
+const (
+	minErrorBackoff = 10 * time.Millisecond
+	maxErrorBackoff = time.Second
+)
+
+// errorBackoff tracks the delay before retrying after an error.
+type errorBackoff time.Duration
+
+// next returns the delay to wait and doubles it for the next error.
+func (b *errorBackoff) next() time.Duration {
+	d := time.Duration(*b)
+	if d < minErrorBackoff {
+		d = minErrorBackoff
+	}
+	*b = errorBackoff(d * 2)
+	if *b > errorBackoff(maxErrorBackoff) {
+		*b = errorBackoff(maxErrorBackoff)
+	}
+	return d
+}
+
+// reset clears the backoff after a successful operation.
+func (b *errorBackoff) reset() {
+	*b = 0
+}
+
+// NewReceiver starts a Receiver for the given radio.
+// The radio must not be used by other goroutines until the Receiver is stopped.
+func NewReceiver(r Interface, opts ReceiverOptions) *Receiver {
+	if opts.QueueDepth <= 0 {
+		opts.QueueDepth = DefaultReceiverOptions.QueueDepth
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultReceiverOptions.Timeout
+	}
+	rcv := &Receiver{
+		radio:   r,
+		opts:    opts,
+		clocked: clocked{opts.Clock},
+		packets: make(chan Packet, opts.QueueDepth),
+		done:    make(chan struct{}),
+	}
+	rcv.wg.Add(1)
+	go rcv.loop()
+	return rcv
+}
+
+// Packets returns the channel on which received packets are delivered.
+// It is closed when the Receiver stops.
+func (rcv *Receiver) Packets() <-chan Packet {
+	return rcv.packets
+}
+
+// Dropped returns the number of packets discarded because the queue was full.
+func (rcv *Receiver) Dropped() int {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return rcv.dropped
+}
+
+// Err returns the most recent error from the radio other than a
+// receive timeout, or nil if there has been none. Such errors are
+// cleared from the radio and retried with an increasing delay,
+// except that a canceled wait, as after CancelWaits, stops the Receiver.
+func (rcv *Receiver) Err() error {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return rcv.err
+}
+
+// Latency returns the distribution of the times from the return of
+// each Receive call until the packet was queued for the consumer.
+func (rcv *Receiver) Latency() Timing {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return rcv.latency
+}
+
+// Stop stops the Receiver after any Receive call in progress returns.
+// It may be called more than once.
+func (rcv *Receiver) Stop() {
+	rcv.stop.Do(func() { close(rcv.done) })
+	rcv.wg.Wait()
+}
+
+func (rcv *Receiver) loop() {
+	defer rcv.wg.Done()
+	defer close(rcv.packets)
+	if rcv.opts.Priority > 0 {
+		restore, _ := SetRealtime(rcv.opts.Priority)
+		defer restore()
+	}
+	var backoff errorBackoff
+	for {
+		select {
+		case <-rcv.done:
+			return
+		default:
+		}
+		data, rssi := rcv.radio.Receive(rcv.opts.Timeout)
+		t := rcv.now()
+		if err := rcv.radio.Error(); err != nil {
+			rcv.radio.SetError(nil)
+			if IsTimeout(err) {
+				continue
+			}
+			rcv.mu.Lock()
+			rcv.err = err
+			rcv.mu.Unlock()
+			if errors.Is(err, ErrWaitCanceled) {
+				return
+			}
+			select {
+			case <-rcv.done:
+				return
+			case <-rcv.after(backoff.next()):
+			}
+			continue
+		}
+		backoff.reset()
+		if data == nil {
+			continue
+		}
+		if !rcv.deliver(Packet{Data: data, RSSI: rssi, Frequency: rcv.radio.Frequency(), Time: t}) {
+			return
+		}
+	}
+}
+
+// deliver queues p according to the overflow policy.
+// It returns false if the Receiver was stopped while blocked.
+func (rcv *Receiver) deliver(p Packet) bool {
+	select {
+	case rcv.packets <- p:
+		rcv.queued(p)
+		return true
+	default:
+	}
+	switch rcv.opts.Overflow {
+	case Block:
+		select {
+		case rcv.packets <- p:
+			rcv.queued(p)
+			return true
+		case <-rcv.done:
+			return false
+		}
+	case DropNewest:
+		rcv.drop()
+		return true
+	}
+	// Drop the oldest packet. The consumer may have emptied
+	// the queue in the meantime, so neither step can block.
+	select {
+	case <-rcv.packets:
+		rcv.drop()
+	default:
+	}
+	select {
+	case rcv.packets <- p:
+		rcv.queued(p)
+	default:
+		rcv.drop()
+	}
+	return true
+}
+
+func (rcv *Receiver) queued(p Packet) {
+	rcv.mu.Lock()
+	rcv.latency.Add(rcv.now().Sub(p.Time))
+	rcv.mu.Unlock()
+}
+
+func (rcv *Receiver) drop() {
+	rcv.mu.Lock()
+	rcv.dropped++
+	rcv.mu.Unlock()
+}
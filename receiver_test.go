@@ -0,0 +1,177 @@
+package radio_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+// countingRadio is a simulated radio that counts calls to Receive
+// and takes delay to report its frequency.
+type countingRadio struct {
+	*sim.Radio
+	delay time.Duration
+
+	mu       sync.Mutex
+	receives int
+}
+
+func (r *countingRadio) Receive(timeout time.Duration) ([]byte, int) {
+	r.mu.Lock()
+	r.receives++
+	r.mu.Unlock()
+	return r.Radio.Receive(timeout)
+}
+
+func (r *countingRadio) Frequency() uint32 {
+	time.Sleep(r.delay)
+	return r.Radio.Frequency()
+}
+
+func (r *countingRadio) Unwrap() radio.Interface { return r.Radio }
+
+func (r *countingRadio) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.receives
+}
+
+func TestReceiverOverflow(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  radio.OverflowPolicy
+		want    []byte
+		dropped int
+	}{
+		{"drop oldest", radio.DropOldest, []byte{2, 3}, 2},
+		{"drop newest", radio.DropNewest, []byte{0, 1}, 2},
+		{"block", radio.Block, []byte{0, 1, 2, 3}, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, b := simPair(t)
+			rcv := radio.NewReceiver(b, radio.ReceiverOptions{
+				QueueDepth: 2,
+				Overflow:   c.policy,
+				Timeout:    10 * time.Millisecond,
+			})
+			defer rcv.Stop()
+			for i := 0; i < 4; i++ {
+				if c.policy == radio.Block && i == 3 {
+					// The loop is blocked with packet 2, so send
+					// packet 3 only after the consumer makes room.
+					break
+				}
+				waitFor(t, func() bool { return b.State() == "Receive" })
+				a.Send([]byte{byte(i)})
+				waitFor(t, func() bool {
+					_, received := b.Counts()
+					return received > i
+				})
+			}
+			if c.policy != radio.Block {
+				// Let the last packet reach the queue.
+				waitFor(t, func() bool { return rcv.Dropped() == c.dropped })
+			}
+			var got []byte
+			for i := range c.want {
+				if c.policy == radio.Block && i == 3 {
+					waitFor(t, func() bool { return b.State() == "Receive" })
+					a.Send([]byte{3})
+				}
+				select {
+				case p := <-rcv.Packets():
+					got = append(got, p.Data[0])
+				case <-time.After(time.Second):
+					t.Fatalf("received %v, want %v", got, c.want)
+				}
+			}
+			if string(got) != string(c.want) {
+				t.Errorf("received %v, want %v", got, c.want)
+			}
+			if n := rcv.Dropped(); n != c.dropped {
+				t.Errorf("dropped %d packets, want %d", n, c.dropped)
+			}
+		})
+	}
+}
+
+func TestReceiverErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		setup   func(r *sim.Radio)
+		want    error
+		stopped bool
+	}{
+		{"closed", func(r *sim.Radio) { r.Close() }, sim.ErrClosed, false},
+		{"canceled", func(r *sim.Radio) { r.SetError(radio.ErrWaitCanceled) }, radio.ErrWaitCanceled, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, b := simPair(t)
+			r := &countingRadio{Radio: b}
+			c.setup(b)
+			rcv := radio.NewReceiver(r, radio.ReceiverOptions{Timeout: time.Millisecond})
+			defer rcv.Stop()
+			waitFor(t, func() bool { return rcv.Err() != nil })
+			if err := rcv.Err(); !errors.Is(err, c.want) {
+				t.Errorf("Err() = %v, want %v", err, c.want)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if n := r.count(); n > 5 {
+				t.Errorf("Receive called %d times in 50ms; the loop is spinning", n)
+			}
+			if c.stopped {
+				select {
+				case _, ok := <-rcv.Packets():
+					if ok {
+						t.Errorf("received a packet after a canceled wait")
+					}
+				case <-time.After(time.Second):
+					t.Errorf("Receiver did not stop after a canceled wait")
+				}
+			}
+		})
+	}
+}
+
+func TestReceiverStopTwice(t *testing.T) {
+	_, _, b := simPair(t)
+	rcv := radio.NewReceiver(b, radio.ReceiverOptions{Timeout: time.Millisecond})
+	rcv.Stop()
+	rcv.Stop()
+}
+
+func TestReceiverLatency(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	_, a, b := simPair(t)
+	rcv := radio.NewReceiver(&countingRadio{Radio: b, delay: delay}, radio.ReceiverOptions{Timeout: 10 * time.Millisecond})
+	defer rcv.Stop()
+	waitFor(t, func() bool { return b.State() == "Receive" })
+	a.Send([]byte{1})
+	select {
+	case <-rcv.Packets():
+	case <-time.After(time.Second):
+		t.Fatal("no packet received")
+	}
+	lat := rcv.Latency()
+	if lat.Count != 1 || lat.Max < delay {
+		t.Errorf("latency = %+v, want one sample of at least %v", lat, delay)
+	}
+}
+
+// waitFor polls cond until it is true or a second has passed.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,67 @@
+package radio
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	flavorsMu sync.Mutex
+	flavors   = make(map[string]func() HardwareFlavor)
+)
+
+// DriverFlavor is implemented by registered flavors whose chip driver
+// can construct a complete radio, so that it can be selected by name
+// in a configuration file (see the config package).
+type DriverFlavor interface {
+	HardwareFlavor
+	// OpenRadio opens the radio using flavor, which is the receiver
+	// with any connection settings overridden.
+	OpenRadio(flavor HardwareFlavor) (Interface, error)
+}
+
+// RegisterFlavor makes a hardware flavor available by the given name,
+// so chip drivers can register themselves from an init function.
+// This is the only registry of radios by name; the config package uses it too.
+// It panics if the name is already registered.
+func RegisterFlavor(name string, factory func() HardwareFlavor) {
+	flavorsMu.Lock()
+	defer flavorsMu.Unlock()
+	if _, dup := flavors[name]; dup {
+		panic("radio: RegisterFlavor called twice for " + name)
+	}
+	flavors[name] = factory
+}
+
+// Flavors returns the names of the registered flavors in sorted order.
+func Flavors() []string {
+	flavorsMu.Lock()
+	defer flavorsMu.Unlock()
+	var names []string
+	for name := range flavors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupFlavor returns a new instance of the named flavor.
+func LookupFlavor(name string) (HardwareFlavor, error) {
+	flavorsMu.Lock()
+	factory := flavors[name]
+	flavorsMu.Unlock()
+	if factory == nil {
+		return nil, fmt.Errorf("unknown radio flavor %q", name)
+	}
+	return factory(), nil
+}
+
+// OpenByName opens the SPI radio module described by the named flavor.
+func OpenByName(name string, options ...Option) *Hardware {
+	flavor, err := LookupFlavor(name)
+	if err != nil {
+		return &Hardware{err: err}
+	}
+	return Open(flavor, options...)
+}
@@ -0,0 +1,46 @@
+package radio
+
+import (
+	"testing"
+)
+
+func TestOpenByName(t *testing.T) {
+	RegisterFlavor("registry-test", func() HardwareFlavor { return testFlavor{} })
+	cases := []struct {
+		name    string
+		options []Option
+		ok      bool
+	}{
+		{"registry-test", []Option{DryRun(nil)}, true},
+		{"no-such-flavor", nil, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := OpenByName(c.name, c.options...)
+			if err := h.Error(); (err == nil) != c.ok {
+				t.Errorf("OpenByName(%q) error = %v", c.name, err)
+			}
+			// Close must be safe even if the device was never opened.
+			h.Close()
+		})
+	}
+}
+
+func TestOpenFromEnvError(t *testing.T) {
+	t.Setenv(EnvSPISpeed, "fast")
+	h := OpenFromEnv(testFlavor{}, DryRun(nil))
+	if h.Error() == nil {
+		t.Errorf("OpenFromEnv with %s=fast succeeded", EnvSPISpeed)
+	}
+	h.Close()
+}
+
+func TestRegisterFlavorTwice(t *testing.T) {
+	RegisterFlavor("registry-twice", func() HardwareFlavor { return testFlavor{} })
+	defer func() {
+		if recover() == nil {
+			t.Errorf("second RegisterFlavor did not panic")
+		}
+	}()
+	RegisterFlavor("registry-twice", func() HardwareFlavor { return testFlavor{} })
+}
@@ -0,0 +1,50 @@
+package radio
+
+import (
+	"errors"
+	"time"
+)
+
+// ResetFlavor is implemented by flavors for modules with a hardware reset line.
+// The line is asserted for the pulse duration and the chip is then
+// given the settle duration to start up.
+type ResetFlavor interface {
+	ResetPin() int
+	ResetActiveLow() bool
+	ResetTiming() (pulse time.Duration, settle time.Duration)
+}
+
+// ErrNoResetPin indicates that the flavor does not declare a reset line.
+var ErrNoResetPin = errors.New("no hardware reset line")
+
+// HardReset resets the chip by toggling its reset line.
+// It sets the error state to ErrNoResetPin if the flavor
+// does not implement ResetFlavor.
+func (h *Hardware) HardReset() {
+	f, ok := h.flavor.(ResetFlavor)
+	if !ok || f.ResetPin() < 0 {
+		h.err = ErrNoResetPin
+		return
+	}
+	if h.isDryRun() {
+		return
+	}
+	if h.reset == nil {
+		h.reset, h.err = openOutput(f.ResetPin(), f.ResetActiveLow(), false)
+		if h.Error() != nil {
+			h.reset = nil
+			return
+		}
+	}
+	pulse, settle := f.ResetTiming()
+	h.err = h.reset.Write(true)
+	if h.Error() != nil {
+		return
+	}
+	time.Sleep(pulse)
+	h.err = h.reset.Write(false)
+	if h.Error() != nil {
+		return
+	}
+	time.Sleep(settle)
+}
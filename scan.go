@@ -0,0 +1,62 @@
+package radio
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReceiveAny listens on each of the given frequencies in turn, for up to
+// dwell at a time, until a packet arrives or the timeout expires.
+// It returns the packet, its RSSI, and the frequency it arrived on,
+// leaving the radio tuned there so that a reply can be sent.
+// If no packet arrives, or the radio fails, the radio is returned to its
+// original frequency and the result is nil with a zero frequency.
+// Receive timeouts on each frequency are cleared from the error state;
+// other errors are left in it. A dwell that is not positive is rejected
+// by setting the error state.
+func ReceiveAny(r Interface, freqs []uint32, dwell time.Duration, timeout time.Duration) (data []byte, rssi int, freq uint32) {
+	if len(freqs) == 0 {
+		return nil, 0, 0
+	}
+	if dwell <= 0 {
+		r.SetError(fmt.Errorf("invalid dwell time (%v)", dwell))
+		return nil, 0, 0
+	}
+	orig := r.Frequency()
+	defer func() {
+		if data != nil || r.Frequency() == orig {
+			return
+		}
+		err := r.Error()
+		r.SetError(nil)
+		r.SetFrequency(orig)
+		if err != nil {
+			r.SetError(err)
+		}
+	}()
+	deadline := time.Now().Add(timeout)
+	for i := 0; ; i = (i + 1) % len(freqs) {
+		left := time.Until(deadline)
+		if left <= 0 {
+			return nil, 0, 0
+		}
+		if left > dwell {
+			left = dwell
+		}
+		f := freqs[i]
+		if r.Frequency() != f {
+			r.SetFrequency(f)
+		}
+		data, rssi = r.Receive(left)
+		if err := r.Error(); err != nil {
+			if IsTimeout(err) {
+				r.SetError(nil)
+				continue
+			}
+			return nil, 0, 0
+		}
+		if data != nil {
+			return data, rssi, f
+		}
+	}
+}
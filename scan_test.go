@@ -0,0 +1,85 @@
+package radio_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+)
+
+// scanRadio is a simulated radio that receives a packet only when
+// tuned to packetOn. Otherwise it fails with fail, if set, or waits
+// out the timeout, reporting it through the error state if timeoutErr.
+type scanRadio struct {
+	*sim.Radio
+	packetOn   uint32
+	fail       error
+	timeoutErr bool
+}
+
+func (r *scanRadio) Receive(timeout time.Duration) ([]byte, int) {
+	if r.fail != nil {
+		r.SetError(r.fail)
+		return nil, 0
+	}
+	if r.Frequency() == r.packetOn {
+		return []byte{1}, -50
+	}
+	time.Sleep(timeout)
+	if r.timeoutErr {
+		r.SetError(radio.ReceiveTimeoutError{Timeout: timeout})
+	}
+	return nil, 0
+}
+
+func (r *scanRadio) Unwrap() radio.Interface { return r.Radio }
+
+func TestReceiveAny(t *testing.T) {
+	const (
+		home = 916500000
+		f1   = 868300000
+		f2   = 868950000
+	)
+	errSPI := errors.New("spi failure")
+	cases := []struct {
+		name  string
+		radio scanRadio
+		dwell time.Duration
+		freq  uint32
+		left  uint32
+		err   error
+	}{
+		{"found", scanRadio{packetOn: f2}, 5 * time.Millisecond, f2, f2, nil},
+		{"found after timeouts", scanRadio{packetOn: f2, timeoutErr: true}, 5 * time.Millisecond, f2, f2, nil},
+		{"nothing", scanRadio{}, 5 * time.Millisecond, 0, home, nil},
+		{"timeout errors", scanRadio{timeoutErr: true}, 5 * time.Millisecond, 0, home, nil},
+		{"failure", scanRadio{fail: errSPI}, 5 * time.Millisecond, 0, home, errSPI},
+		{"zero dwell", scanRadio{packetOn: f1}, 0, 0, home, nil},
+		{"negative dwell", scanRadio{packetOn: f1}, -time.Millisecond, 0, home, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := c.radio
+			r.Radio = sim.NewMedium().NewRadio("r")
+			r.Init(home)
+			data, _, freq := radio.ReceiveAny(&r, []uint32{f1, f2}, c.dwell, 30*time.Millisecond)
+			if freq != c.freq || (data != nil) != (c.freq != 0) {
+				t.Errorf("received %v on %d, want a packet on %d", data, freq, c.freq)
+			}
+			if got := r.Frequency(); got != c.left {
+				t.Errorf("left on %d, want %d", got, c.left)
+			}
+			err := r.Error()
+			switch {
+			case c.dwell <= 0:
+				if err == nil {
+					t.Errorf("dwell %v was accepted", c.dwell)
+				}
+			case !errors.Is(err, c.err):
+				t.Errorf("error = %v, want %v", err, c.err)
+			}
+		})
+	}
+}
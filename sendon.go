@@ -0,0 +1,57 @@
+package radio
+
+import (
+	"errors"
+)
+
+// ErrNoDataRate indicates that a radio does not support changing its data rate.
+var ErrNoDataRate = errors.New("radio does not support setting the data rate")
+
+// SendOn transmits data at the given frequency and data rate, then
+// restores the radio's previous frequency and data rate.
+// A zero frequency or rate leaves that setting unchanged.
+// Changing the rate requires a DataRater in the wrapped chain;
+// otherwise nothing is sent and the error state is set to ErrNoDataRate.
+// Callers sharing r between goroutines must serialize access to it,
+// for example with a TransmitQueue, for the change to be atomic.
+func SendOn(r Interface, freq uint32, rate uint32, data []byte) {
+	var d DataRater
+	if rate != 0 {
+		var ok bool
+		d, ok = Find(r, isDataRater).(DataRater)
+		if !ok {
+			r.SetError(ErrNoDataRate)
+			return
+		}
+	}
+	prevFreq := r.Frequency()
+	if freq != 0 && freq != prevFreq {
+		r.SetFrequency(freq)
+	} else {
+		freq = 0
+	}
+	var prevRate uint32
+	if d != nil {
+		prevRate = d.DataRate()
+		if rate != prevRate {
+			d.SetDataRate(rate)
+		} else {
+			d = nil
+		}
+	}
+	if r.Error() == nil {
+		r.Send(data)
+	}
+	// Restoring the settings writes registers, which can overwrite
+	// the error state, so keep the first error.
+	err := r.Error()
+	if d != nil {
+		d.SetDataRate(prevRate)
+	}
+	if freq != 0 {
+		r.SetFrequency(prevFreq)
+	}
+	if err != nil {
+		r.SetError(err)
+	}
+}
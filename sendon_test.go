@@ -0,0 +1,62 @@
+package radio_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ecc1/radio"
+)
+
+// failingSend is a radio whose Send fails and whose
+// SetFrequency clears the error state, as register writes do.
+type failingSend struct {
+	*rateRadio
+}
+
+var errSend = errors.New("send failed")
+
+func (r failingSend) Send([]byte) { r.SetError(errSend) }
+
+func (r failingSend) SetFrequency(freq uint32) {
+	r.rateRadio.SetFrequency(freq)
+	r.SetError(nil)
+}
+
+func TestSendOn(t *testing.T) {
+	cases := []struct {
+		name string
+		freq uint32
+		rate uint32
+		fail bool
+	}{
+		{"Unchanged", 0, 0, false},
+		{"Frequency", 916600000, 0, false},
+		{"Rate", 0, 9600, false},
+		{"Both", 916600000, 9600, false},
+		{"FailedSend", 916600000, 9600, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			rr := &rateRadio{Radio: a, rate: 38400}
+			var r radio.Interface = rr
+			if c.fail {
+				r = failingSend{rr}
+			}
+			radio.SendOn(r, c.freq, c.rate, []byte{1, 2, 3})
+			if got := r.Frequency(); got != 916500000 {
+				t.Errorf("frequency not restored: %d", got)
+			}
+			if rr.rate != 38400 {
+				t.Errorf("data rate not restored: %d", rr.rate)
+			}
+			want := error(nil)
+			if c.fail {
+				want = errSend
+			}
+			if err := r.Error(); err != want {
+				t.Errorf("error = %v, want %v", err, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package radio
+
+// Settings describes how a radio module is connected.
+type Settings struct {
+	SPIDevice    string
+	Speed        int
+	CustomCS     int
+	InterruptPin int
+}
+
+// Overrides replaces some of the connection settings of a flavor.
+// An empty SPIDevice, a zero Speed, and nil pins keep the flavor's
+// own values. The pins are pointers so that pin 0 can be selected.
+type Overrides struct {
+	SPIDevice    string
+	Speed        int
+	CustomCS     *int
+	InterruptPin *int
+}
+
+// Pin returns a pointer to n, for use in Overrides.
+func Pin(n int) *int {
+	return &n
+}
+
+// settingsFlavor overrides the connection parameters of a flavor.
+type settingsFlavor struct {
+	HardwareFlavor
+	overrides Overrides
+}
+
+// WithSettings returns a flavor that uses the settings given in o
+// in place of the corresponding values of flavor.
+// Hardware opened with the result still uses any optional
+// interfaces implemented by flavor itself.
+func WithSettings(flavor HardwareFlavor, o Overrides) HardwareFlavor {
+	return settingsFlavor{HardwareFlavor: flavor, overrides: o}
+}
+
+func (f settingsFlavor) SPIDevice() string {
+	if f.overrides.SPIDevice != "" {
+		return f.overrides.SPIDevice
+	}
+	return f.HardwareFlavor.SPIDevice()
+}
+
+func (f settingsFlavor) Speed() int {
+	if f.overrides.Speed != 0 {
+		return f.overrides.Speed
+	}
+	return f.HardwareFlavor.Speed()
+}
+
+func (f settingsFlavor) CustomCS() int {
+	if f.overrides.CustomCS != nil {
+		return *f.overrides.CustomCS
+	}
+	return f.HardwareFlavor.CustomCS()
+}
+
+func (f settingsFlavor) InterruptPin() int {
+	if f.overrides.InterruptPin != nil {
+		return *f.overrides.InterruptPin
+	}
+	return f.HardwareFlavor.InterruptPin()
+}
+
+// resolveSettings returns the underlying flavor, without any
+// settingsFlavor wrappers, and the settings to open it with.
+func resolveSettings(flavor HardwareFlavor) (HardwareFlavor, Settings) {
+	s := Settings{
+		SPIDevice:    flavor.SPIDevice(),
+		Speed:        flavor.Speed(),
+		CustomCS:     flavor.CustomCS(),
+		InterruptPin: flavor.InterruptPin(),
+	}
+	for {
+		f, ok := flavor.(settingsFlavor)
+		if !ok {
+			return flavor, s
+		}
+		flavor = f.HardwareFlavor
+	}
+}
+
+// Settings returns the settings the device was opened with.
+func (h *Hardware) Settings() Settings {
+	return h.settings
+}
@@ -0,0 +1,53 @@
+package radio
+
+import (
+	"testing"
+)
+
+func TestWithSettings(t *testing.T) {
+	defaults := Settings{SPIDevice: "/dev/spidev0.0", Speed: 1000000, CustomCS: 0, InterruptPin: 24}
+	cases := []struct {
+		name   string
+		flavor HardwareFlavor
+		want   Settings
+	}{
+		{"none", testFlavor{}, defaults},
+		{"empty", WithSettings(testFlavor{}, Overrides{}), defaults},
+		{
+			"all",
+			WithSettings(testFlavor{}, Overrides{SPIDevice: "/dev/spidev1.1", Speed: 4000000, CustomCS: Pin(8), InterruptPin: Pin(25)}),
+			Settings{SPIDevice: "/dev/spidev1.1", Speed: 4000000, CustomCS: 8, InterruptPin: 25},
+		},
+		{
+			"pin 0",
+			WithSettings(testFlavor{}, Overrides{InterruptPin: Pin(0)}),
+			Settings{SPIDevice: "/dev/spidev0.0", Speed: 1000000, CustomCS: 0, InterruptPin: 0},
+		},
+		{
+			"nested",
+			WithSettings(WithSettings(testFlavor{}, Overrides{SPIDevice: "/dev/spidev1.0", InterruptPin: Pin(25)}), Overrides{Speed: 500000, InterruptPin: Pin(-1)}),
+			Settings{SPIDevice: "/dev/spidev1.0", Speed: 500000, CustomCS: 0, InterruptPin: -1},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// The flavor's own accessors must agree with what Open uses.
+			accessed := Settings{
+				SPIDevice:    c.flavor.SPIDevice(),
+				Speed:        c.flavor.Speed(),
+				CustomCS:     c.flavor.CustomCS(),
+				InterruptPin: c.flavor.InterruptPin(),
+			}
+			if accessed != c.want {
+				t.Errorf("flavor reports %+v, want %+v", accessed, c.want)
+			}
+			flavor, s := resolveSettings(c.flavor)
+			if s != c.want {
+				t.Errorf("resolved %+v, want %+v", s, c.want)
+			}
+			if _, ok := flavor.(testFlavor); !ok {
+				t.Errorf("resolved flavor is %T, want testFlavor", flavor)
+			}
+		})
+	}
+}
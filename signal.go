@@ -0,0 +1,62 @@
+package radio
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// HardwareAccessor is implemented by drivers that expose their
+// underlying Hardware device.
+type HardwareAccessor interface {
+	Hardware() *Hardware
+}
+
+func isHardwareAccessor(r Interface) bool {
+	_, ok := r.(HardwareAccessor)
+	return ok
+}
+
+// ShutdownOnSignal shuts r down cleanly when the process receives
+// SIGINT or SIGTERM. Interrupt waits in progress are canceled
+// (when a HardwareAccessor is in the wrapped chain), each stop function
+// is called in order to drain queues and stop background goroutines,
+// and the radio is then reset, leaving the chip idle, and closed.
+// Waits are allowed again before the reset, since resetting the chip
+// may itself need to wait for it.
+// The returned channel is closed when shutdown is complete;
+// the caller typically waits on it and then exits.
+//
+//	rcv := radio.NewReceiver(r, radio.DefaultReceiverOptions)
+//	done := radio.ShutdownOnSignal(r, rcv.Stop)
+//	...
+//	<-done
+func ShutdownOnSignal(r Interface, stop ...func()) <-chan struct{} {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		<-sig
+		signal.Stop(sig)
+		Shutdown(r, stop...)
+		close(done)
+	}()
+	return done
+}
+
+// Shutdown performs the same steps as ShutdownOnSignal, immediately.
+func Shutdown(r Interface, stop ...func()) {
+	a, ok := Find(r, isHardwareAccessor).(HardwareAccessor)
+	if ok {
+		a.Hardware().CancelWaits()
+	}
+	for _, f := range stop {
+		f()
+	}
+	if ok {
+		a.Hardware().resumeWaits()
+	}
+	r.SetError(nil)
+	r.Reset()
+	r.Close()
+}
@@ -0,0 +1,51 @@
+package radio
+
+import (
+	"testing"
+	"time"
+)
+
+// statusFlavor reports the interrupt condition in a status register.
+type statusFlavor struct{ testFlavor }
+
+func (statusFlavor) InterruptPin() int             { return -1 }
+func (statusFlavor) InterruptStatus() (byte, byte) { return 0x3F, 0x01 }
+
+func TestShutdown(t *testing.T) {
+	cases := []struct {
+		name   string
+		status byte
+		reset  error
+	}{
+		{"chip ready", 0x01, nil},
+		{"chip stuck", 0x00, InterruptTimeoutError{Pin: -1, Timeout: 10 * time.Millisecond}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Open(statusFlavor{}, func(h *Hardware) { h.device = &statusDevice{status: c.status} })
+			var stopErr, resetErr error
+			reset := false
+			r := &hwRadio{hw: h, reset: func(h *Hardware) {
+				reset = true
+				h.AwaitInterrupt(10 * time.Millisecond)
+				resetErr = h.Error()
+			}}
+			stop := func() {
+				// Waits stay canceled while the stop functions run.
+				h.AwaitInterrupt(10 * time.Millisecond)
+				stopErr = h.Error()
+				h.SetError(nil)
+			}
+			Shutdown(r, stop)
+			if stopErr != ErrWaitCanceled {
+				t.Errorf("wait during stop returned %v, want %v", stopErr, ErrWaitCanceled)
+			}
+			if !reset {
+				t.Fatal("radio was not reset")
+			}
+			if resetErr != c.reset {
+				t.Errorf("wait during reset returned %v, want %v", resetErr, c.reset)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+// Package sim provides simulated radios connected by a virtual RF medium,
+// so that code built on radio.Interface can be exercised without hardware.
+//
+// A packet sent by one radio reaches every other radio on the same
+// medium that is tuned to the same frequency and is receiving when the
+// packet arrives, subject to the loss, bit error rate, and propagation
+// delay of the link between them.
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+// Link describes the path from one simulated radio to another.
+type Link struct {
+	// Loss is the probability that a packet is lost entirely.
+	Loss float64
+	// BitErrorRate is the probability that each bit is inverted.
+	BitErrorRate float64
+	// Delay is the propagation delay.
+	Delay time.Duration
+	// RSSI is the signal strength reported for delivered packets, in dBm.
+	RSSI int
+	// Disconnected prevents any packets from being delivered.
+	Disconnected bool
+}
+
+// DefaultLink is the link used between radios with no link configured.
+var DefaultLink = Link{RSSI: -60}
+
+type linkKey struct {
+	from, to *Radio
+}
+
+// Medium connects simulated radios.
+type Medium struct {
+	mu     sync.Mutex
+	radios []*Radio
+	links  map[linkKey]Link
+	rand   *rand.Rand
+}
+
+// NewMedium returns an empty medium.
+func NewMedium() *Medium {
+	return &Medium{
+		links: make(map[linkKey]Link),
+		rand:  rand.New(radio.NewSource(nil)),
+	}
+}
+
+// Seed makes the medium's packet loss and bit errors reproducible.
+func (m *Medium) Seed(seed int64) {
+	m.SetSource(rand.NewSource(seed))
+}
+
+// SetSource sets the source of randomness for packet loss and bit errors.
+func (m *Medium) SetSource(src rand.Source) {
+	m.mu.Lock()
+	m.rand = rand.New(src)
+	m.mu.Unlock()
+}
+
+// SetLink sets the link from one radio to another.
+// Links are directional; call SetLinks for both directions.
+func (m *Medium) SetLink(from, to *Radio, l Link) {
+	m.mu.Lock()
+	m.links[linkKey{from, to}] = l
+	m.mu.Unlock()
+}
+
+// SetLinks sets the links in both directions between two radios.
+func (m *Medium) SetLinks(a, b *Radio, l Link) {
+	m.SetLink(a, b, l)
+	m.SetLink(b, a, l)
+}
+
+func (m *Medium) link(from, to *Radio) Link {
+	l, ok := m.links[linkKey{from, to}]
+	if !ok {
+		return DefaultLink
+	}
+	return l
+}
+
+// NewRadio adds a radio with the given name to the medium.
+func (m *Medium) NewRadio(name string) *Radio {
+	r := &Radio{medium: m, name: name, state: "Idle", inbox: make(chan delivery, 16)}
+	m.mu.Lock()
+	m.radios = append(m.radios, r)
+	m.mu.Unlock()
+	return r
+}
+
+// transmit schedules delivery of data sent by r to the other radios.
+func (m *Medium) transmit(from *Radio, freq uint32, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, to := range m.radios {
+		if to == from {
+			continue
+		}
+		l := m.link(from, to)
+		if l.Disconnected || m.rand.Float64() < l.Loss {
+			continue
+		}
+		pkt := append([]byte(nil), data...)
+		if l.BitErrorRate > 0 {
+			for i := range pkt {
+				for bit := uint(0); bit < 8; bit++ {
+					if m.rand.Float64() < l.BitErrorRate {
+						pkt[i] ^= 1 << bit
+					}
+				}
+			}
+		}
+		d := delivery{data: pkt, rssi: l.RSSI, freq: freq}
+		to := to
+		if l.Delay <= 0 {
+			to.deliver(d)
+		} else {
+			time.AfterFunc(l.Delay, func() { to.deliver(d) })
+		}
+	}
+}
+
+type delivery struct {
+	data []byte
+	rssi int
+	freq uint32
+}
+
+// ErrClosed indicates an operation on a closed simulated radio.
+var ErrClosed = errors.New("simulated radio is closed")
+
+// Radio is a simulated radio that implements radio.Interface.
+type Radio struct {
+	medium *Medium
+	name   string
+	inbox  chan delivery
+
+	mu        sync.Mutex
+	freq      uint32
+	state     string
+	listening bool
+	closed    bool
+	err       error
+	sent      int
+	received  int
+}
+
+var _ radio.Interface = (*Radio)(nil)
+
+// deliver queues a packet if the radio is receiving on its frequency.
+func (r *Radio) deliver(d delivery) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.listening || r.closed || d.freq != r.freq {
+		return
+	}
+	select {
+	case r.inbox <- d:
+	default:
+	}
+}
+
+// Init initializes the radio to the given frequency.
+func (r *Radio) Init(frequency uint32) {
+	r.mu.Lock()
+	r.freq = frequency
+	r.state = "Idle"
+	r.closed = false
+	r.mu.Unlock()
+}
+
+// Reset returns the radio to the idle state.
+func (r *Radio) Reset() {
+	r.mu.Lock()
+	r.state = "Idle"
+	r.mu.Unlock()
+}
+
+// Close closes the radio.
+func (r *Radio) Close() {
+	r.mu.Lock()
+	r.closed = true
+	r.state = "Closed"
+	r.mu.Unlock()
+}
+
+// Frequency returns the radio's frequency.
+func (r *Radio) Frequency() uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.freq
+}
+
+// SetFrequency sets the radio's frequency.
+func (r *Radio) SetFrequency(freq uint32) {
+	r.mu.Lock()
+	r.freq = freq
+	r.mu.Unlock()
+}
+
+// Send transmits data to the other radios on the medium.
+func (r *Radio) Send(data []byte) {
+	r.mu.Lock()
+	if r.closed {
+		r.err = ErrClosed
+		r.mu.Unlock()
+		return
+	}
+	r.sent++
+	freq := r.freq
+	r.mu.Unlock()
+	r.medium.transmit(r, freq, data)
+}
+
+// Receive waits up to timeout for a packet.
+func (r *Radio) Receive(timeout time.Duration) ([]byte, int) {
+	r.mu.Lock()
+	if r.closed {
+		r.err = ErrClosed
+		r.mu.Unlock()
+		return nil, 0
+	}
+	r.listening = true
+	r.state = "Receive"
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.listening = false
+		r.state = "Idle"
+		r.mu.Unlock()
+	}()
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case d := <-r.inbox:
+		r.mu.Lock()
+		r.received++
+		r.mu.Unlock()
+		return d.data, d.rssi
+	case <-t.C:
+		return nil, 0
+	}
+}
+
+// SendAndReceive transmits data and then waits up to timeout for a reply.
+// The radio starts listening before transmitting, so replies
+// sent with no delay are not missed.
+func (r *Radio) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	r.mu.Lock()
+	r.listening = true
+	r.mu.Unlock()
+	r.Send(data)
+	if r.Error() != nil {
+		return nil, 0
+	}
+	return r.Receive(timeout)
+}
+
+// State returns the radio's state.
+func (r *Radio) State() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// Error returns the error state of the radio.
+func (r *Radio) Error() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// SetError sets the error state of the radio.
+func (r *Radio) SetError(err error) {
+	r.mu.Lock()
+	r.err = err
+	r.mu.Unlock()
+}
+
+// Name returns the name of the radio type.
+func (r *Radio) Name() string {
+	return "sim"
+}
+
+// Device returns the name the radio was created with.
+func (r *Radio) Device() string {
+	return r.name
+}
+
+// Counts returns the number of packets the radio has sent and received.
+func (r *Radio) Counts() (sent, received int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sent, r.received
+}
+
+func (r *Radio) String() string {
+	return fmt.Sprintf("sim radio %s at %s MHz", r.name, radio.MegaHertz(r.Frequency()))
+}
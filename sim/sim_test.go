@@ -0,0 +1,126 @@
+package sim_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio/sim"
+)
+
+const freq = 916500000
+
+// exchange sends data from a to b and returns what b receives, if anything.
+func exchange(t *testing.T, a, b *sim.Radio, data []byte) ([]byte, int, time.Duration) {
+	t.Helper()
+	type result struct {
+		data []byte
+		rssi int
+	}
+	got := make(chan result, 1)
+	go func() {
+		data, rssi := b.Receive(100 * time.Millisecond)
+		got <- result{data, rssi}
+	}()
+	deadline := time.Now().Add(time.Second)
+	for b.State() != "Receive" {
+		if time.Now().After(deadline) {
+			t.Fatal("receiver never started listening")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	start := time.Now()
+	a.Send(data)
+	r := <-got
+	return r.data, r.rssi, time.Since(start)
+}
+
+func TestLinks(t *testing.T) {
+	packet := []byte{0x55, 0xAA, 0x0F}
+	cases := []struct {
+		name     string
+		link     *sim.Link
+		freq     uint32
+		want     []byte
+		rssi     int
+		minDelay time.Duration
+	}{
+		{name: "default", want: packet, rssi: sim.DefaultLink.RSSI},
+		{name: "rssi", link: &sim.Link{RSSI: -95}, want: packet, rssi: -95},
+		{name: "disconnected", link: &sim.Link{Disconnected: true}},
+		{name: "lossy", link: &sim.Link{Loss: 1}},
+		{name: "bit errors", link: &sim.Link{BitErrorRate: 1, RSSI: -70}, want: []byte{0xAA, 0x55, 0xF0}, rssi: -70},
+		{name: "delay", link: &sim.Link{Delay: 20 * time.Millisecond, RSSI: -60}, want: packet, rssi: -60, minDelay: 20 * time.Millisecond},
+		{name: "other frequency", freq: freq + 200000},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sim.NewMedium()
+			a, b := m.NewRadio("a"), m.NewRadio("b")
+			a.Init(freq)
+			b.Init(freq)
+			if c.freq != 0 {
+				b.SetFrequency(c.freq)
+			}
+			if c.link != nil {
+				m.SetLink(a, b, *c.link)
+			}
+			data, rssi, elapsed := exchange(t, a, b, packet)
+			if !bytes.Equal(data, c.want) {
+				t.Fatalf("received % X, want % X", data, c.want)
+			}
+			if data != nil && rssi != c.rssi {
+				t.Errorf("RSSI = %d, want %d", rssi, c.rssi)
+			}
+			if elapsed < c.minDelay {
+				t.Errorf("delivered after %v, want at least %v", elapsed, c.minDelay)
+			}
+			sent, _ := a.Counts()
+			_, received := b.Counts()
+			if sent != 1 || received != len(c.want)/len(packet) {
+				t.Errorf("counts = %d sent, %d received", sent, received)
+			}
+		})
+	}
+}
+
+func TestDirectionalLink(t *testing.T) {
+	m := sim.NewMedium()
+	a, b := m.NewRadio("a"), m.NewRadio("b")
+	a.Init(freq)
+	b.Init(freq)
+	m.SetLink(a, b, sim.Link{Disconnected: true})
+	if data, _, _ := exchange(t, a, b, []byte{1}); data != nil {
+		t.Errorf("packet crossed a disconnected link")
+	}
+	if data, _, _ := exchange(t, b, a, []byte{2}); data == nil {
+		t.Errorf("packet lost on the reverse link")
+	}
+}
+
+func TestClosed(t *testing.T) {
+	cases := []struct {
+		name string
+		op   func(r *sim.Radio)
+	}{
+		{"Send", func(r *sim.Radio) { r.Send([]byte{1}) }},
+		{"Receive", func(r *sim.Radio) { r.Receive(time.Second) }},
+		{"SendAndReceive", func(r *sim.Radio) { r.SendAndReceive([]byte{1}, time.Second) }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := sim.NewMedium().NewRadio("r")
+			r.Init(freq)
+			r.Close()
+			start := time.Now()
+			c.op(r)
+			if !errors.Is(r.Error(), sim.ErrClosed) {
+				t.Errorf("Error() = %v, want %v", r.Error(), sim.ErrClosed)
+			}
+			if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+				t.Errorf("took %v on a closed radio", elapsed)
+			}
+		})
+	}
+}
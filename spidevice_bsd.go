@@ -0,0 +1,14 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package radio
+
+import (
+	"github.com/ecc1/spi"
+)
+
+// SPIDevice returns the radio's SPI device, which is always nil
+// on this platform, since devices open as stubs.
+func (h *Hardware) SPIDevice() *spi.Device {
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package radio
+
+// SPIDevice stands in for spi.Device on platforms such as Windows,
+// where that package does not build.
+type SPIDevice struct{}
+
+// SPIDevice returns the radio's SPI device, which is always nil
+// on this platform, since devices open as stubs.
+func (h *Hardware) SPIDevice() *SPIDevice {
+	return nil
+}
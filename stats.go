@@ -0,0 +1,93 @@
+package radio
+
+import (
+	"time"
+)
+
+// Timing accumulates the durations of a repeated operation.
+type Timing struct {
+	Count     int
+	Total     time.Duration
+	Min       time.Duration
+	Max       time.Duration
+	Histogram Histogram
+}
+
+// Add records a single duration.
+func (t *Timing) Add(d time.Duration) {
+	if t.Count == 0 || d < t.Min {
+		t.Min = d
+	}
+	if d > t.Max {
+		t.Max = d
+	}
+	t.Count++
+	t.Total += d
+	t.Histogram.Record(d)
+}
+
+// Mean returns the average of the recorded durations.
+func (t Timing) Mean() time.Duration {
+	if t.Count == 0 {
+		return 0
+	}
+	return t.Total / time.Duration(t.Count)
+}
+
+// Stats records per-stage latencies of radio hardware operations,
+// which are only measured while profiling is enabled,
+// and counts of FIFO errors and recoveries and inter-packet gaps,
+// which always are.
+// Wakeups records how late interrupt waits that timed out returned
+// relative to their deadline, and Transmits the time AwaitTransmit
+// waited for each transmission to complete.
+// Drivers can use Turnaround to record the time between the end
+// of a reception and the start of the following transmission.
+// Gaps records the delays imposed by a PacketGap wrapper.
+type Stats struct {
+	Transfers  Timing
+	Interrupts Timing
+	Wakeups    Timing
+	Transmits  Timing
+	Turnaround Timing
+	Gaps       Timing
+
+	Overflows  int
+	Underflows int
+	Recoveries int
+}
+
+// SetProfiling enables or disables latency measurements.
+func (h *Hardware) SetProfiling(enabled bool) {
+	h.profiling = enabled
+}
+
+// Profiling returns whether latency measurements are enabled.
+func (h *Hardware) Profiling() bool {
+	return h.profiling
+}
+
+// Stats returns the statistics collected by the device.
+func (h *Hardware) Stats() Stats {
+	return h.stats
+}
+
+// ResetStats clears the collected statistics.
+func (h *Hardware) ResetStats() {
+	h.stats = Stats{}
+}
+
+// RecordTurnaround records a receive-to-send turnaround time
+// measured by the driver, if profiling is enabled.
+func (h *Hardware) RecordTurnaround(d time.Duration) {
+	if h.profiling {
+		h.stats.Turnaround.Add(d)
+	}
+}
+
+// RecordGap records a delay imposed to enforce a minimum
+// inter-packet gap. Gaps are recorded whether or not profiling
+// is enabled, since they are imposed rather than measured.
+func (h *Hardware) RecordGap(d time.Duration) {
+	h.stats.Gaps.Add(d)
+}
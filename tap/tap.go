@@ -0,0 +1,235 @@
+// Package tap connects a radio to UDP sockets, so that packets can be
+// captured and injected by other programs.
+//
+// Each packet received over the air is sent as one datagram to a
+// remote address, and each datagram received on a local address
+// is transmitted over the air.
+package tap
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+const (
+	// listenTime is how long the radio listens before checking for datagrams to send.
+	listenTime = 50 * time.Millisecond
+	// queueDepth is the number of datagrams waiting to be transmitted
+	// beyond which further datagrams are dropped.
+	queueDepth  = 16
+	maxDatagram = 1 << 16
+	// minBackoff and maxBackoff bound the delay before retrying
+	// after an error other than a receive timeout.
+	minBackoff = 10 * time.Millisecond
+	maxBackoff = time.Second
+)
+
+// Tap relays packets between a radio and UDP sockets.
+type Tap struct {
+	radio  radio.Interface
+	conn   *net.UDPConn
+	remote *net.UDPAddr
+	send   chan []byte
+	done   chan struct{}
+	stop   sync.Once
+	wg     sync.WaitGroup
+	// closeErr is the error from closing the socket.
+	closeErr error
+
+	mu       sync.Mutex
+	err      error
+	received int
+	sent     int
+	dropped  int
+}
+
+// New starts a Tap that sends received packets to the remote address
+// and transmits datagrams arriving on the local address.
+// The remote address may be empty to only inject packets.
+// The radio must not be used by other goroutines until the Tap is closed.
+func New(r radio.Interface, local, remote string) (*Tap, error) {
+	laddr, err := net.ResolveUDPAddr("udp", local)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tap{
+		radio: r,
+		send:  make(chan []byte, queueDepth),
+		done:  make(chan struct{}),
+	}
+	if remote != "" {
+		t.remote, err = net.ResolveUDPAddr("udp", remote)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.conn, err = net.ListenUDP("udp", laddr)
+	if err != nil {
+		return nil, err
+	}
+	t.wg.Add(2)
+	go t.readLoop()
+	go t.radioLoop()
+	return t, nil
+}
+
+// LocalAddr returns the address on which the Tap accepts datagrams.
+func (t *Tap) LocalAddr() net.Addr {
+	return t.conn.LocalAddr()
+}
+
+// Counts returns the number of packets received over the air,
+// transmitted over the air, and dropped because the transmit queue was full.
+func (t *Tap) Counts() (received, sent, dropped int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.received, t.sent, t.dropped
+}
+
+// Err returns the most recent error from the radio or socket, other than
+// a receive timeout, or nil if there has been none. Such errors are
+// retried with an increasing delay, except that a canceled radio wait,
+// as after CancelWaits, stops the Tap from using the radio.
+func (t *Tap) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Close stops the Tap and closes its socket.
+// It may be called more than once; later calls return
+// the same result as the first.
+func (t *Tap) Close() error {
+	t.stop.Do(func() {
+		close(t.done)
+		t.closeErr = t.conn.Close()
+	})
+	t.wg.Wait()
+	return t.closeErr
+}
+
+func (t *Tap) readLoop() {
+	defer t.wg.Done()
+	buf := make([]byte, maxDatagram)
+	var delay time.Duration
+	for {
+		n, _, err := t.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-t.done:
+				return
+			default:
+			}
+			t.setErr(err)
+			if !t.backoff(&delay) {
+				return
+			}
+			continue
+		}
+		delay = 0
+		data := append([]byte(nil), buf[:n]...)
+		select {
+		case t.send <- data:
+		default:
+			t.mu.Lock()
+			t.dropped++
+			t.mu.Unlock()
+		}
+	}
+}
+
+func (t *Tap) radioLoop() {
+	defer t.wg.Done()
+	r := t.radio
+	var delay time.Duration
+	for {
+		select {
+		case <-t.done:
+			return
+		case data := <-t.send:
+			r.Send(data)
+			if err := t.radioErr(); err != nil {
+				if !t.retry(err, &delay) {
+					return
+				}
+				continue
+			}
+			delay = 0
+			t.mu.Lock()
+			t.sent++
+			t.mu.Unlock()
+			continue
+		default:
+		}
+		data, _ := r.Receive(listenTime)
+		if err := t.radioErr(); err != nil {
+			if !t.retry(err, &delay) {
+				return
+			}
+			continue
+		}
+		delay = 0
+		if data == nil {
+			continue
+		}
+		t.mu.Lock()
+		t.received++
+		t.mu.Unlock()
+		if t.remote != nil {
+			_, _ = t.conn.WriteToUDP(data, t.remote)
+		}
+	}
+}
+
+// radioErr clears the radio's error state and returns
+// any error other than a receive timeout.
+func (t *Tap) radioErr() error {
+	err := t.radio.Error()
+	if err == nil {
+		return nil
+	}
+	t.radio.SetError(nil)
+	if radio.IsTimeout(err) {
+		return nil
+	}
+	return err
+}
+
+// retry records a radio error and backs off.
+// It returns false if the Tap should stop using the radio.
+func (t *Tap) retry(err error, delay *time.Duration) bool {
+	t.setErr(err)
+	if errors.Is(err, radio.ErrWaitCanceled) {
+		return false
+	}
+	return t.backoff(delay)
+}
+
+func (t *Tap) setErr(err error) {
+	t.mu.Lock()
+	t.err = err
+	t.mu.Unlock()
+}
+
+// backoff waits before retrying after an error, doubling delay up to
+// maxBackoff for next time. It returns false if the Tap was closed.
+func (t *Tap) backoff(delay *time.Duration) bool {
+	d := *delay
+	if d < minBackoff {
+		d = minBackoff
+	}
+	*delay = 2 * d
+	if *delay > maxBackoff {
+		*delay = maxBackoff
+	}
+	select {
+	case <-t.done:
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
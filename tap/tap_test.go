@@ -0,0 +1,149 @@
+package tap_test
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+	"github.com/ecc1/radio/sim"
+	"github.com/ecc1/radio/tap"
+)
+
+const freq = 916500000
+
+func TestTap(t *testing.T) {
+	cases := []struct {
+		name   string
+		inject bool
+		data   []byte
+	}{
+		{"capture", false, []byte{1, 2, 3}},
+		{"inject", true, []byte{4, 5, 6}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sim.NewMedium()
+			r, peer := m.NewRadio("tap"), m.NewRadio("peer")
+			r.Init(freq)
+			peer.Init(freq)
+			remote, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer remote.Close()
+			tp, err := tap.New(r, "127.0.0.1:0", remote.LocalAddr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tp.Close()
+			var got []byte
+			if c.inject {
+				received := make(chan []byte, 1)
+				go func() {
+					data, _ := peer.Receive(time.Second)
+					received <- data
+				}()
+				waitFor(t, func() bool { return peer.State() == "Receive" })
+				if _, err := remote.WriteTo(c.data, tp.LocalAddr()); err != nil {
+					t.Fatal(err)
+				}
+				got = <-received
+			} else {
+				waitFor(t, func() bool { return r.State() == "Receive" })
+				peer.Send(c.data)
+				buf := make([]byte, 64)
+				_ = remote.SetReadDeadline(time.Now().Add(time.Second))
+				n, _, err := remote.ReadFrom(buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = buf[:n]
+			}
+			if !bytes.Equal(got, c.data) {
+				t.Errorf("relayed %v, want %v", got, c.data)
+			}
+		})
+	}
+}
+
+// countingRadio is a simulated radio that counts calls to Receive.
+type countingRadio struct {
+	*sim.Radio
+
+	mu       sync.Mutex
+	receives int
+}
+
+func (r *countingRadio) Receive(timeout time.Duration) ([]byte, int) {
+	r.mu.Lock()
+	r.receives++
+	r.mu.Unlock()
+	return r.Radio.Receive(timeout)
+}
+
+func (r *countingRadio) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.receives
+}
+
+func TestTapErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(r *sim.Radio)
+		want  error
+	}{
+		{"closed", func(r *sim.Radio) { r.Close() }, sim.ErrClosed},
+		{"canceled", func(r *sim.Radio) { r.SetError(radio.ErrWaitCanceled) }, radio.ErrWaitCanceled},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := &countingRadio{Radio: sim.NewMedium().NewRadio("tap")}
+			r.Init(freq)
+			c.setup(r.Radio)
+			tp, err := tap.New(r, "127.0.0.1:0", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tp.Close()
+			waitFor(t, func() bool { return tp.Err() != nil })
+			if err := tp.Err(); !errors.Is(err, c.want) {
+				t.Errorf("Err() = %v, want %v", err, c.want)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if n := r.count(); n > 5 {
+				t.Errorf("Receive called %d times in 50ms; the loop is spinning", n)
+			}
+		})
+	}
+}
+
+func TestTapCloseTwice(t *testing.T) {
+	r := sim.NewMedium().NewRadio("tap")
+	r.Init(freq)
+	tp, err := tap.New(r, "127.0.0.1:0", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := tp.Close(); err != nil {
+			t.Errorf("Close %d: %v", i+1, err)
+		}
+	}
+}
+
+// waitFor polls cond until it is true or a second has passed.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
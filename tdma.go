@@ -0,0 +1,129 @@
+package radio
+
+import (
+	"fmt"
+	"time"
+)
+
+// TDMAConfig describes a TDMA frame: a repeating sequence of
+// Slots time slots of SlotLength each, starting at Epoch on the
+// network clock. Each node transmits only in its own Slot.
+type TDMAConfig struct {
+	SlotLength time.Duration
+	Slots      int
+	Slot       int
+	// Guard is the time left unused at each end of a slot
+	// to absorb residual clock error.
+	Guard time.Duration
+	Epoch time.Time
+	// Clock provides the time. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// DefaultTDMAConfig is used for zero SlotLength and Slots
+// fields of TDMAConfig. A single slot lets the node transmit at any time.
+var DefaultTDMAConfig = TDMAConfig{
+	SlotLength: 100 * time.Millisecond,
+	Slots:      1,
+}
+
+// TDMA wraps a radio so that Send only transmits during the node's
+// own slot, waiting for it if necessary. Receiving is unrestricted,
+// so the radio listens in every other slot.
+// The network clock is the local clock adjusted by a TimeSync
+// with the node that defines it, or the local clock itself on that node.
+type TDMA struct {
+	Interface
+	config TDMAConfig
+	sync   *TimeSync
+	clocked
+}
+
+// NewTDMA returns a TDMA wrapper for r.
+// The sync argument may be nil on the node whose clock defines the network time.
+// An error is returned if the node's Slot is not in the frame
+// or if the guard times leave no part of a slot for transmitting.
+func NewTDMA(r Interface, config TDMAConfig, sync *TimeSync) (*TDMA, error) {
+	if config.SlotLength == 0 {
+		config.SlotLength = DefaultTDMAConfig.SlotLength
+	}
+	if config.Slots == 0 {
+		config.Slots = DefaultTDMAConfig.Slots
+	}
+	switch {
+	case config.SlotLength < 0:
+		return nil, fmt.Errorf("invalid TDMA slot length (%v)", config.SlotLength)
+	case config.Slots < 0:
+		return nil, fmt.Errorf("invalid number of TDMA slots (%d)", config.Slots)
+	case config.Slot < 0 || config.Slot >= config.Slots:
+		return nil, fmt.Errorf("TDMA slot %d out of range (should be less than %d)", config.Slot, config.Slots)
+	case config.Guard < 0 || 2*config.Guard >= config.SlotLength:
+		return nil, fmt.Errorf("invalid TDMA guard time (%v) for slot length %v", config.Guard, config.SlotLength)
+	}
+	return &TDMA{Interface: r, config: config, sync: sync, clocked: clocked{config.Clock}}, nil
+}
+
+// Unwrap returns the radio wrapped by t.
+func (t *TDMA) Unwrap() Interface {
+	return t.Interface
+}
+
+// Config returns the TDMA frame configuration.
+func (t *TDMA) Config() TDMAConfig {
+	return t.config
+}
+
+// NetworkTime converts a local time to the network clock.
+func (t *TDMA) NetworkTime(local time.Time) time.Time {
+	if t.sync == nil {
+		return local
+	}
+	return t.sync.RemoteTime(local)
+}
+
+func (t *TDMA) frameLength() time.Duration {
+	return time.Duration(t.config.Slots) * t.config.SlotLength
+}
+
+// position returns the slot in progress at local time now
+// and how far into that slot it is.
+func (t *TDMA) position(now time.Time) (slot int, into time.Duration) {
+	since := t.NetworkTime(now).Sub(t.config.Epoch) % t.frameLength()
+	if since < 0 {
+		since += t.frameLength()
+	}
+	return int(since / t.config.SlotLength), since % t.config.SlotLength
+}
+
+// CurrentSlot returns the slot in progress.
+func (t *TDMA) CurrentSlot() int {
+	slot, _ := t.position(t.now())
+	return slot
+}
+
+// untilTransmit returns how long to wait before the node may transmit.
+func (t *TDMA) untilTransmit(now time.Time) time.Duration {
+	slot, into := t.position(now)
+	if slot == t.config.Slot && into >= t.config.Guard && into < t.config.SlotLength-t.config.Guard {
+		return 0
+	}
+	slots := (t.config.Slot - slot + t.config.Slots) % t.config.Slots
+	wait := time.Duration(slots)*t.config.SlotLength - into + t.config.Guard
+	if wait <= 0 {
+		wait += t.frameLength()
+	}
+	return wait
+}
+
+// Send waits for the node's slot and then transmits data.
+func (t *TDMA) Send(data []byte) {
+	t.sleep(t.untilTransmit(t.now()))
+	t.Interface.Send(data)
+}
+
+// SendAndReceive waits for the node's slot, transmits data,
+// and then receives a reply with the given timeout.
+func (t *TDMA) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	t.sleep(t.untilTransmit(t.now()))
+	return t.Interface.SendAndReceive(data, timeout)
+}
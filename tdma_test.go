@@ -0,0 +1,69 @@
+package radio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTDMA(t *testing.T) {
+	epoch := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	const ms = time.Millisecond
+	four := TDMAConfig{SlotLength: 100 * ms, Slots: 4, Slot: 2, Guard: 10 * ms, Epoch: epoch}
+	cases := []struct {
+		name   string
+		config TDMAConfig
+		at     time.Duration
+		slot   int
+		wait   time.Duration
+	}{
+		{"in own slot", four, 250 * ms, 2, 0},
+		{"leading guard", four, 205 * ms, 2, 5 * ms},
+		{"trailing guard", four, 295 * ms, 2, 315 * ms},
+		{"earlier slot", four, 50 * ms, 0, 160 * ms},
+		{"later slot", four, 350 * ms, 3, 260 * ms},
+		{"next frame", four, 1050 * ms, 2, 0},
+		{"before epoch", four, -150 * ms, 2, 0},
+		{"zero config", TDMAConfig{Epoch: epoch}, 250 * ms, 0, 0},
+		{"zero slots", TDMAConfig{SlotLength: 100 * ms, Epoch: epoch}, 250 * ms, 0, 0},
+		{"zero slot length", TDMAConfig{Slots: 4, Slot: 1, Epoch: epoch}, 50 * ms, 0, 50 * ms},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tdma, err := NewTDMA(nil, c.config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			at := epoch.Add(c.at)
+			if slot, _ := tdma.position(at); slot != c.slot {
+				t.Errorf("slot = %d, want %d", slot, c.slot)
+			}
+			if wait := tdma.untilTransmit(at); wait != c.wait {
+				t.Errorf("wait = %v, want %v", wait, c.wait)
+			}
+		})
+	}
+}
+
+func TestTDMAConfigErrors(t *testing.T) {
+	const ms = time.Millisecond
+	cases := []struct {
+		name   string
+		config TDMAConfig
+	}{
+		{"negative slot", TDMAConfig{SlotLength: 100 * ms, Slots: 4, Slot: -1}},
+		{"slot past frame", TDMAConfig{SlotLength: 100 * ms, Slots: 4, Slot: 4}},
+		{"slot with default slots", TDMAConfig{SlotLength: 100 * ms, Slot: 1}},
+		{"negative slots", TDMAConfig{SlotLength: 100 * ms, Slots: -4}},
+		{"negative slot length", TDMAConfig{SlotLength: -100 * ms, Slots: 4}},
+		{"negative guard", TDMAConfig{SlotLength: 100 * ms, Slots: 4, Guard: -ms}},
+		{"guard fills slot", TDMAConfig{SlotLength: 100 * ms, Slots: 4, Guard: 50 * ms}},
+		{"guard exceeds default slot length", TDMAConfig{Slots: 4, Guard: 60 * ms}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := NewTDMA(nil, c.config, nil); err == nil {
+				t.Errorf("NewTDMA(%+v) succeeded", c.config)
+			}
+		})
+	}
+}
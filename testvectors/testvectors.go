@@ -0,0 +1,87 @@
+// Package testvectors provides canonical input and output values for
+// the pure functions in the radio package, so that chip drivers and
+// other implementations can check their own conversions against
+// the same data.
+package testvectors
+
+// MegaHertzVector pairs a frequency with its radio.MegaHertz formatting.
+type MegaHertzVector struct {
+	Hertz     uint32
+	MegaHertz string
+}
+
+// MegaHertz holds vectors for radio.MegaHertz.
+// Formatting truncates to whole kiloHertz.
+var MegaHertz = []MegaHertzVector{
+	{Hertz: 0, MegaHertz: "  0.000"},
+	{Hertz: 50000000, MegaHertz: " 50.000"},
+	{Hertz: 315000000, MegaHertz: "315.000"},
+	{Hertz: 433920000, MegaHertz: "433.920"},
+	{Hertz: 868299987, MegaHertz: "868.299"},
+	{Hertz: 916500000, MegaHertz: "916.500"},
+	{Hertz: 2450000000, MegaHertz: "2450.000"},
+	{Hertz: 4294967295, MegaHertz: "4294.967"},
+}
+
+// ParseVector gives the result of radio.ParseMegaHertz for an input.
+// Valid is false if the input must be rejected.
+type ParseVector struct {
+	Input string
+	Hertz uint32
+	Valid bool
+}
+
+// ParseMegaHertz holds vectors for radio.ParseMegaHertz.
+var ParseMegaHertz = []ParseVector{
+	{Input: "916.5", Hertz: 916500000, Valid: true},
+	{Input: "916.500", Hertz: 916500000, Valid: true},
+	{Input: " 50.000", Hertz: 50000000, Valid: true},
+	{Input: "868.3", Hertz: 868300000, Valid: true},
+	{Input: "915", Hertz: 915000000, Valid: true},
+	{Input: "433.920001", Hertz: 433920001, Valid: true},
+	{Input: "4294.967295", Hertz: 4294967295, Valid: true},
+	{Input: "4294.967296"},
+	{Input: "433.9200001"},
+	{Input: ".5"},
+	{Input: "-1"},
+	{Input: "916,5"},
+	{Input: ""},
+}
+
+// RegisterVector gives the frequency register value for a frequency,
+// for a synthesizer whose step is Crystal / 2^Shift, and the frequency
+// that register value actually tunes to. Both conversions truncate.
+type RegisterVector struct {
+	Crystal  uint32
+	Shift    uint
+	Hertz    uint32
+	Register uint32
+	Tuned    uint32
+}
+
+// Registers holds vectors for radio.FrequencyToRegister and
+// radio.RegisterToFrequency, covering the RFM69 (32 MHz, 2^19)
+// and CC1101/CC111x (26 MHz and 24 MHz, 2^16) synthesizers.
+var Registers = []RegisterVector{
+	{Crystal: 32000000, Shift: 19, Hertz: 315000000, Register: 5160960, Tuned: 315000000},
+	{Crystal: 32000000, Shift: 19, Hertz: 433920000, Register: 7109345, Tuned: 433919982},
+	{Crystal: 32000000, Shift: 19, Hertz: 868000000, Register: 14221312, Tuned: 868000000},
+	{Crystal: 32000000, Shift: 19, Hertz: 868300000, Register: 14226227, Tuned: 868299987},
+	{Crystal: 32000000, Shift: 19, Hertz: 915000000, Register: 14991360, Tuned: 915000000},
+	{Crystal: 32000000, Shift: 19, Hertz: 916500000, Register: 15015936, Tuned: 916500000},
+	{Crystal: 32000000, Shift: 19, Hertz: 916600000, Register: 15017574, Tuned: 916599975},
+	{Crystal: 26000000, Shift: 16, Hertz: 315000000, Register: 793993, Tuned: 314999664},
+	{Crystal: 26000000, Shift: 16, Hertz: 433920000, Register: 1093745, Tuned: 433919830},
+	{Crystal: 26000000, Shift: 16, Hertz: 868000000, Register: 2187894, Tuned: 867999938},
+	{Crystal: 26000000, Shift: 16, Hertz: 868300000, Register: 2188650, Tuned: 868299865},
+	{Crystal: 26000000, Shift: 16, Hertz: 915000000, Register: 2306363, Tuned: 914999969},
+	{Crystal: 26000000, Shift: 16, Hertz: 916500000, Register: 2310144, Tuned: 916500000},
+	{Crystal: 26000000, Shift: 16, Hertz: 916600000, Register: 2310396, Tuned: 916599975},
+	{Crystal: 24000000, Shift: 16, Hertz: 315000000, Register: 860160, Tuned: 315000000},
+	{Crystal: 24000000, Shift: 16, Hertz: 433920000, Register: 1184890, Tuned: 433919677},
+	{Crystal: 24000000, Shift: 16, Hertz: 868000000, Register: 2370218, Tuned: 867999755},
+	{Crystal: 24000000, Shift: 16, Hertz: 868300000, Register: 2371037, Tuned: 868299682},
+	{Crystal: 24000000, Shift: 16, Hertz: 915000000, Register: 2498560, Tuned: 915000000},
+	{Crystal: 24000000, Shift: 16, Hertz: 916500000, Register: 2502656, Tuned: 916500000},
+	{Crystal: 24000000, Shift: 16, Hertz: 916600000, Register: 2502929, Tuned: 916599975},
+}
@@ -0,0 +1,88 @@
+package radio
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timeouts bounds the time spent in radio operations that wait on the chip.
+type Timeouts struct {
+	// Interrupt is used by AwaitInterrupt when it is given a non-positive timeout.
+	Interrupt time.Duration
+	// Transmit bounds the wait for a transmission to complete.
+	Transmit time.Duration
+	// StateChange bounds the wait for the chip to enter a new operating state.
+	StateChange time.Duration
+}
+
+// DefaultTimeouts are the timeouts used by a newly opened Hardware device.
+var DefaultTimeouts = Timeouts{
+	Interrupt:   time.Second,
+	Transmit:    time.Second,
+	StateChange: 100 * time.Millisecond,
+}
+
+// OperationTimeoutError indicates that a wait for the chip timed out.
+type OperationTimeoutError struct {
+	Operation string
+	Timeout   time.Duration
+}
+
+func (e OperationTimeoutError) Error() string {
+	return fmt.Sprintf("%s timeout after %v", e.Operation, e.Timeout)
+}
+
+// Timeouts returns the device's operation timeouts.
+func (h *Hardware) Timeouts() Timeouts {
+	return h.timeouts
+}
+
+// SetTimeouts sets the device's operation timeouts.
+// Zero fields are replaced by the corresponding DefaultTimeouts.
+func (h *Hardware) SetTimeouts(t Timeouts) {
+	if t.Interrupt <= 0 {
+		t.Interrupt = DefaultTimeouts.Interrupt
+	}
+	if t.Transmit <= 0 {
+		t.Transmit = DefaultTimeouts.Transmit
+	}
+	if t.StateChange <= 0 {
+		t.StateChange = DefaultTimeouts.StateChange
+	}
+	h.timeouts = t
+}
+
+// AwaitStateChange polls ready until it returns true or the
+// state change timeout expires, in which case the error state
+// is set to an OperationTimeoutError.
+func (h *Hardware) AwaitStateChange(ready func() bool) {
+	h.poll("state change", h.timeouts.StateChange, ready)
+}
+
+// poll calls done every poll interval until it returns true,
+// the timeout expires, or the wait is canceled.
+func (h *Hardware) poll(op string, timeout time.Duration, done func() bool) {
+	deadline := h.now().Add(timeout)
+	for h.Error() == nil {
+		if done() {
+			return
+		}
+		left := h.until(deadline)
+		if left <= 0 {
+			h.err = OperationTimeoutError{Operation: op, Timeout: timeout}
+			return
+		}
+		if h.ctx != nil && h.ctx.Err() != nil {
+			h.err = h.ctx.Err()
+			return
+		}
+		if h.waitsCanceled() {
+			h.err = ErrWaitCanceled
+			return
+		}
+		if h.pollInterval > 0 && left > h.pollInterval {
+			left = h.pollInterval
+		}
+		h.sleep(left)
+	}
+}
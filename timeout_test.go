@@ -0,0 +1,68 @@
+package radio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAwaitStateChange(t *testing.T) {
+	cases := []struct {
+		name   string
+		ready  bool
+		cancel bool
+		want   error
+	}{
+		{"ready", true, false, nil},
+		{"timeout", false, false, OperationTimeoutError{Operation: "state change", Timeout: 20 * time.Millisecond}},
+		{"canceled", false, true, ErrWaitCanceled},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := openTest()
+			defer h.Close()
+			h.SetTimeouts(Timeouts{StateChange: 20 * time.Millisecond})
+			if c.cancel {
+				h.CancelWaits()
+			}
+			start := time.Now()
+			h.AwaitStateChange(func() bool { return c.ready })
+			if err := h.Error(); err != c.want {
+				t.Errorf("error = %v, want %v", err, c.want)
+			}
+			if c.cancel && time.Since(start) >= 20*time.Millisecond {
+				t.Errorf("canceled wait took %v", time.Since(start))
+			}
+		})
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	cases := []struct {
+		name     string
+		interval time.Duration
+		min, max int
+	}{
+		{"default", DefaultPollInterval, 5, 60},
+		{"coarse", 10 * time.Millisecond, 2, 6},
+		{"zero", 0, 1, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := openTest()
+			defer h.Close()
+			h.SetTimeouts(Timeouts{StateChange: 40 * time.Millisecond})
+			h.SetPollInterval(c.interval)
+			n := 0
+			h.AwaitStateChange(func() bool {
+				n++
+				return false
+			})
+			if _, ok := h.Error().(OperationTimeoutError); !ok {
+				t.Errorf("error = %v, want a timeout", h.Error())
+			}
+			if n < c.min || n > c.max {
+				t.Errorf("checked %d times in 40ms, want %d to %d", n, c.min, c.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package radio
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Time synchronization packets begin with a two-byte tag and a type,
+// followed by big-endian Unix nanosecond timestamps:
+//
+//	request: 'T' 'S' 1 t1
+//	reply:   'T' 'S' 2 t1 t2 t3
+//
+// where t1 is when the request was sent, t2 when it was received,
+// and t3 when the reply was sent.
+const (
+	syncTag0       = 'T'
+	syncTag1       = 'S'
+	syncRequest    = 1
+	syncReply      = 2
+	syncRequestLen = 3 + 8
+	syncReplyLen   = 3 + 3*8
+)
+
+// DefaultTimeSyncSamples is used for a zero or negative TimeSync.MaxSamples.
+const DefaultTimeSyncSamples = 8
+
+// ErrNoTimeSyncReply indicates that no valid time synchronization reply was received.
+var ErrNoTimeSyncReply = errors.New("no time sync reply")
+
+type syncSample struct {
+	local  time.Time
+	offset time.Duration
+}
+
+// TimeSync estimates the offset and drift of a remote node's clock
+// from request/reply exchanges, in the manner of NTP.
+type TimeSync struct {
+	radio Interface
+	// MaxSamples is the number of recent exchanges used for the drift estimate.
+	// If zero or negative, DefaultTimeSyncSamples is used.
+	MaxSamples int
+	// Clock provides the local time. If nil, SystemClock is used.
+	Clock Clock
+
+	mu      sync.Mutex
+	samples []syncSample
+}
+
+// NewTimeSync returns a TimeSync that exchanges packets using r,
+// keeping the DefaultTimeSyncSamples most recent samples.
+func NewTimeSync(r Interface) *TimeSync {
+	return &TimeSync{radio: r, MaxSamples: DefaultTimeSyncSamples}
+}
+
+// Exchange performs one request/reply exchange with the remote node,
+// which must be running ServeTimeSync, and returns the measured clock
+// offset (remote minus local) and round-trip delay.
+func (s *TimeSync) Exchange(timeout time.Duration) (offset time.Duration, delay time.Duration, err error) {
+	req := make([]byte, syncRequestLen)
+	req[0], req[1], req[2] = syncTag0, syncTag1, syncRequest
+	c := clocked{s.Clock}
+	t1 := c.now()
+	putTime(req[3:], t1)
+	reply, _ := s.radio.SendAndReceive(req, timeout)
+	t4 := c.now()
+	if err := s.radio.Error(); err != nil {
+		return 0, 0, err
+	}
+	if !isSyncPacket(reply, syncReply, syncReplyLen) || !getTime(reply[3:]).Equal(t1) {
+		return 0, 0, ErrNoTimeSyncReply
+	}
+	t2 := getTime(reply[11:])
+	t3 := getTime(reply[19:])
+	offset = (t2.Sub(t1) + t3.Sub(t4)) / 2
+	delay = t4.Sub(t1) - t3.Sub(t2)
+	s.addSample(syncSample{local: t4, offset: offset})
+	return offset, delay, nil
+}
+
+func (s *TimeSync) addSample(x syncSample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	max := s.MaxSamples
+	if max <= 0 {
+		max = DefaultTimeSyncSamples
+	}
+	s.samples = append(s.samples, x)
+	if len(s.samples) > max {
+		s.samples = s.samples[len(s.samples)-max:]
+	}
+}
+
+// estimate returns the least-squares fit offset = base + drift*(t - origin).
+func (s *TimeSync) estimate() (origin time.Time, base float64, drift float64, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.samples)
+	if n == 0 {
+		return time.Time{}, 0, 0, false
+	}
+	origin = s.samples[0].local
+	var sx, sy, sxx, sxy float64
+	for _, x := range s.samples {
+		dx := x.local.Sub(origin).Seconds()
+		dy := float64(x.offset)
+		sx += dx
+		sy += dy
+		sxx += dx * dx
+		sxy += dx * dy
+	}
+	fn := float64(n)
+	d := fn*sxx - sx*sx
+	if n == 1 || d == 0 {
+		return origin, sy / fn, 0, true
+	}
+	drift = (fn*sxy - sx*sy) / d
+	base = (sy - drift*sx) / fn
+	return origin, base, drift, true
+}
+
+// Synchronized reports whether at least one exchange has succeeded.
+func (s *TimeSync) Synchronized() bool {
+	_, _, _, ok := s.estimate()
+	return ok
+}
+
+// Drift returns the estimated rate at which the remote clock gains on
+// the local one, in nanoseconds per second.
+func (s *TimeSync) Drift() float64 {
+	_, _, drift, _ := s.estimate()
+	return drift
+}
+
+// Offset returns the estimated offset of the remote clock at local time t.
+func (s *TimeSync) Offset(t time.Time) time.Duration {
+	origin, base, drift, ok := s.estimate()
+	if !ok {
+		return 0
+	}
+	return time.Duration(base + drift*t.Sub(origin).Seconds())
+}
+
+// RemoteTime converts a local time to the remote node's clock.
+func (s *TimeSync) RemoteTime(local time.Time) time.Time {
+	return local.Add(s.Offset(local))
+}
+
+// LocalTime converts a time on the remote node's clock to local time.
+func (s *TimeSync) LocalTime(remote time.Time) time.Time {
+	// The offset changes slowly enough that evaluating it
+	// at the remote time itself is accurate.
+	return remote.Add(-s.Offset(remote))
+}
+
+// ServeTimeSync waits up to timeout for a time synchronization request
+// and answers it. Other packets are returned to the caller; the result
+// is nil if a request was answered or nothing was received.
+// The reply carries the time of clock, or of SystemClock if it is nil.
+func ServeTimeSync(r Interface, timeout time.Duration, clock Clock) []byte {
+	c := clocked{clock}
+	data, _ := r.Receive(timeout)
+	t2 := c.now()
+	if r.Error() != nil || !isSyncPacket(data, syncRequest, syncRequestLen) {
+		return data
+	}
+	reply := make([]byte, syncReplyLen)
+	reply[0], reply[1], reply[2] = syncTag0, syncTag1, syncReply
+	copy(reply[3:11], data[3:11])
+	putTime(reply[11:], t2)
+	putTime(reply[19:], c.now())
+	r.Send(reply)
+	return nil
+}
+
+func isSyncPacket(data []byte, kind byte, n int) bool {
+	return len(data) == n && data[0] == syncTag0 && data[1] == syncTag1 && data[2] == kind
+}
+
+func putTime(b []byte, t time.Time) {
+	binary.BigEndian.PutUint64(b, uint64(t.UnixNano()))
+}
+
+func getTime(b []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(b)))
+}
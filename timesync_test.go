@@ -0,0 +1,78 @@
+package radio_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+// syncStep is an exchange at a local time with a given remote clock offset.
+type syncStep struct {
+	at, offset time.Duration
+}
+
+func TestTimeSync(t *testing.T) {
+	const s, ms = time.Second, time.Millisecond
+	steps := []syncStep{{0, 5 * s}, {s, 5 * s}, {2 * s, 5*s + ms}}
+	cases := []struct {
+		name       string
+		maxSamples int
+		steps      []syncStep
+		drift      float64
+	}{
+		{"one exchange", 8, steps[:1], 0},
+		{"all samples", 8, steps, 0.5e6},
+		{"window", 2, steps, 1e6},
+		{"zero max samples", 0, steps, 0.5e6},
+		{"negative max samples", -1, steps, 0.5e6},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, b := simPair(t)
+			start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			local := radio.NewFakeClock(start)
+			remote := radio.NewFakeClock(start)
+			ts := radio.NewTimeSync(a)
+			ts.MaxSamples = c.maxSamples
+			ts.Clock = local
+			var last syncStep
+			for i, step := range c.steps {
+				local.Advance(step.at - last.at)
+				remote.Advance(step.at - last.at + step.offset - last.offset)
+				last = step
+				served := make(chan []byte, 1)
+				go func() { served <- radio.ServeTimeSync(b, time.Second, remote) }()
+				waitFor(t, func() bool { return b.State() == "Receive" })
+				offset, delay, err := ts.Exchange(time.Second)
+				if err != nil {
+					t.Fatalf("exchange %d: %v", i, err)
+				}
+				if data := <-served; data != nil {
+					t.Errorf("exchange %d: ServeTimeSync returned % X", i, data)
+				}
+				if offset != step.offset || delay != 0 {
+					t.Errorf("exchange %d: offset %v, delay %v, want %v, 0", i, offset, delay, step.offset)
+				}
+			}
+			if !ts.Synchronized() {
+				t.Fatal("not synchronized")
+			}
+			if drift := ts.Drift(); math.Abs(drift-c.drift) > 1 {
+				t.Errorf("drift = %v, want %v", drift, c.drift)
+			}
+		})
+	}
+}
+
+func TestTimeSyncNoReply(t *testing.T) {
+	_, a, _ := simPair(t)
+	ts := radio.NewTimeSync(a)
+	if _, _, err := ts.Exchange(10 * time.Millisecond); err != radio.ErrNoTimeSyncReply {
+		t.Errorf("error = %v, want %v", err, radio.ErrNoTimeSyncReply)
+	}
+	if ts.Synchronized() {
+		t.Error("synchronized without a reply")
+	}
+}
@@ -0,0 +1,126 @@
+package radio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// A trace is a sequence of packets, each encoded as a fixed-size
+// big-endian header followed by the packet data:
+//
+//	timestamp  int64   nanoseconds since the Unix epoch
+//	frequency  uint32  Hertz
+//	rssi       int16   dBm
+//	length     uint16  number of data bytes
+const traceHeaderLen = 8 + 4 + 2 + 2
+
+// MaxTracePacketLength is the largest packet that can be stored in a trace.
+const MaxTracePacketLength = 1<<16 - 1
+
+// TraceWriter writes packets to a trace.
+type TraceWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewTraceWriter returns a TraceWriter that writes to w.
+func NewTraceWriter(w io.Writer) *TraceWriter {
+	return &TraceWriter{w: w}
+}
+
+// Write appends p to the trace.
+// The header and data are written in a single call,
+// so a failed write cannot leave a header without its data.
+func (t *TraceWriter) Write(p Packet) error {
+	n := len(p.Data)
+	if n > MaxTracePacketLength {
+		return fmt.Errorf("packet length (%d) exceeds trace limit", n)
+	}
+	if cap(t.buf) < traceHeaderLen+n {
+		t.buf = make([]byte, traceHeaderLen+n)
+	}
+	b := t.buf[:traceHeaderLen+n]
+	binary.BigEndian.PutUint64(b[0:], uint64(p.Time.UnixNano()))
+	binary.BigEndian.PutUint32(b[8:], p.Frequency)
+	binary.BigEndian.PutUint16(b[12:], uint16(int16(p.RSSI)))
+	binary.BigEndian.PutUint16(b[14:], uint16(n))
+	copy(b[traceHeaderLen:], p.Data)
+	_, err := t.w.Write(b)
+	return err
+}
+
+// TraceReader reads packets from a trace.
+type TraceReader struct {
+	r   io.Reader
+	hdr [traceHeaderLen]byte
+}
+
+// NewTraceReader returns a TraceReader that reads from r.
+func NewTraceReader(r io.Reader) *TraceReader {
+	return &TraceReader{r: r}
+}
+
+// Read returns the next packet in the trace,
+// or io.EOF when there are no more packets.
+func (t *TraceReader) Read() (Packet, error) {
+	_, err := io.ReadFull(t.r, t.hdr[:])
+	if err != nil {
+		return Packet{}, err
+	}
+	p := Packet{
+		Time:      time.Unix(0, int64(binary.BigEndian.Uint64(t.hdr[0:]))),
+		Frequency: binary.BigEndian.Uint32(t.hdr[8:]),
+		RSSI:      int(int16(binary.BigEndian.Uint16(t.hdr[12:]))),
+		Data:      make([]byte, binary.BigEndian.Uint16(t.hdr[14:])),
+	}
+	_, err = io.ReadFull(t.r, p.Data)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return p, err
+}
+
+// ReplayOptions configures Replay.
+type ReplayOptions struct {
+	// Retune sets the radio to each packet's recorded frequency before sending it.
+	Retune bool
+	// NoDelay sends packets back to back instead of with their original spacing.
+	NoDelay bool
+	// Clock provides the time. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// Replay transmits the packets in a trace read from src,
+// preserving their original inter-packet timing.
+// Each packet is scheduled relative to the first one,
+// so the time taken to send does not accumulate.
+func Replay(r Interface, src io.Reader, opts ReplayOptions) error {
+	t := NewTraceReader(src)
+	c := clocked{opts.Clock}
+	var first, start time.Time
+	for {
+		p, err := t.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !opts.NoDelay {
+			if start.IsZero() {
+				first, start = p.Time, c.now()
+			} else {
+				c.sleep(c.until(start.Add(p.Time.Sub(first))))
+			}
+		}
+		if opts.Retune && p.Frequency != 0 && p.Frequency != r.Frequency() {
+			r.SetFrequency(p.Frequency)
+		}
+		r.Send(p.Data)
+		if r.Error() != nil {
+			return r.Error()
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package radio_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+// slowRadio is a recording radio whose Send takes delay.
+type slowRadio struct {
+	*recorder
+	delay time.Duration
+}
+
+func (r *slowRadio) Send(data []byte) {
+	time.Sleep(r.delay)
+	r.recorder.Send(data)
+}
+
+func (r *slowRadio) Unwrap() radio.Interface { return r.recorder }
+
+func TestReplay(t *testing.T) {
+	const (
+		spacing = 30 * time.Millisecond
+		send    = 20 * time.Millisecond
+		n       = 5
+	)
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var trace bytes.Buffer
+	w := radio.NewTraceWriter(&trace)
+	var want [][]byte
+	for i := 0; i < n; i++ {
+		p := radio.Packet{
+			Data:      []byte{byte(i)},
+			Frequency: 916500000 + uint32(i%2)*100000,
+			Time:      t0.Add(time.Duration(i) * spacing),
+		}
+		if err := w.Write(p); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, p.Data)
+	}
+	cases := []struct {
+		name  string
+		opts  radio.ReplayOptions
+		freqs []uint32
+		min   time.Duration
+		max   time.Duration
+	}{
+		// Without drift, the last packet starts (n-1)*spacing after the first.
+		{"timed", radio.ReplayOptions{}, []uint32{916500000, 916500000, 916500000, 916500000, 916500000}, (n - 1) * spacing, (n-1)*spacing + send + (n-1)*send/2},
+		{"no delay", radio.ReplayOptions{NoDelay: true}, []uint32{916500000, 916500000, 916500000, 916500000, 916500000}, n * send, (n-1)*spacing + send},
+		{"retune", radio.ReplayOptions{NoDelay: true, Retune: true}, []uint32{916500000, 916600000, 916500000, 916600000, 916500000}, n * send, (n-1)*spacing + send},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			r := &slowRadio{recorder: &recorder{Radio: a}, delay: send}
+			start := time.Now()
+			if err := radio.Replay(r, bytes.NewReader(trace.Bytes()), c.opts); err != nil {
+				t.Fatal(err)
+			}
+			if d := time.Since(start); d < c.min || d > c.max {
+				t.Errorf("replay took %v, want between %v and %v", d, c.min, c.max)
+			}
+			if got := r.packets(); !reflect.DeepEqual(got, want) {
+				t.Errorf("sent %v, want %v", got, want)
+			}
+			if got := r.frequencies(); !reflect.DeepEqual(got, c.freqs) {
+				t.Errorf("sent on %v, want %v", got, c.freqs)
+			}
+		})
+	}
+}
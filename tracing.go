@@ -0,0 +1,140 @@
+package radio
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Tracer starts spans for radio operations. It mirrors the shape of
+// an OpenTelemetry trace.Tracer, so a few lines of adapter code let
+// radio operations appear in distributed traces alongside other calls,
+// without this package depending on a tracing library.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a key-value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span attribute keys used by a Traced radio.
+const (
+	FrequencyAttribute      = "radio.frequency"
+	PayloadLengthAttribute  = "radio.payload_length"
+	ResponseLengthAttribute = "radio.response_length"
+	RSSIAttribute           = "radio.rssi"
+	RetriesAttribute        = "radio.retries"
+)
+
+// Traced wraps a radio to record a span for each Init, Send, Receive,
+// and SendAndReceive call. Spans are children of the context set with
+// SetContext. Retries are reported when the wrapped chain includes a CSMA.
+type Traced struct {
+	Interface
+
+	tracer Tracer
+	mu     sync.Mutex
+	ctx    context.Context
+}
+
+// NewTraced returns a Traced wrapper for r that uses the given tracer.
+func NewTraced(r Interface, tracer Tracer) *Traced {
+	return &Traced{Interface: r, tracer: tracer, ctx: context.Background()}
+}
+
+// WithTracer returns middleware that traces radio operations.
+func WithTracer(tracer Tracer) Middleware {
+	return func(r Interface) Interface { return NewTraced(r, tracer) }
+}
+
+// Unwrap returns the radio wrapped by t.
+func (t *Traced) Unwrap() Interface {
+	return t.Interface
+}
+
+// SetContext sets the parent context for subsequent spans,
+// typically that of the request being served. A nil context
+// means context.Background.
+func (t *Traced) SetContext(ctx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	t.mu.Lock()
+	t.ctx = ctx
+	t.mu.Unlock()
+}
+
+// start begins a span and returns a function that ends it,
+// recording the error state and any CSMA retries.
+func (t *Traced) start(name string, attrs ...Attribute) (Span, func()) {
+	t.mu.Lock()
+	ctx := t.ctx
+	t.mu.Unlock()
+	_, span := t.tracer.Start(ctx, name)
+	span.SetAttributes(append(attrs, Attribute{FrequencyAttribute, t.Frequency()})...)
+	csma, _ := Find(t.Interface, isCSMA).(*CSMA)
+	retries := 0
+	if csma != nil {
+		retries = csma.Stats().Retries
+	}
+	return span, func() {
+		if csma != nil {
+			span.SetAttributes(Attribute{RetriesAttribute, csma.Stats().Retries - retries})
+		}
+		if err := t.Error(); err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}
+}
+
+// Init initializes the radio within a span.
+func (t *Traced) Init(frequency uint32) {
+	_, end := t.start("radio.Init")
+	t.Interface.Init(frequency)
+	end()
+}
+
+// Send transmits data within a span.
+func (t *Traced) Send(data []byte) {
+	_, end := t.start("radio.Send", Attribute{PayloadLengthAttribute, len(data)})
+	t.Interface.Send(data)
+	end()
+}
+
+// Receive listens for a packet within a span.
+func (t *Traced) Receive(timeout time.Duration) ([]byte, int) {
+	span, end := t.start("radio.Receive")
+	data, rssi := t.Interface.Receive(timeout)
+	if data != nil {
+		span.SetAttributes(Attribute{PayloadLengthAttribute, len(data)}, Attribute{RSSIAttribute, rssi})
+	}
+	end()
+	return data, rssi
+}
+
+// SendAndReceive transmits data and listens for a response within a span.
+func (t *Traced) SendAndReceive(data []byte, timeout time.Duration) ([]byte, int) {
+	span, end := t.start("radio.SendAndReceive", Attribute{PayloadLengthAttribute, len(data)})
+	resp, rssi := t.Interface.SendAndReceive(data, timeout)
+	if resp != nil {
+		span.SetAttributes(Attribute{ResponseLengthAttribute, len(resp)}, Attribute{RSSIAttribute, rssi})
+	}
+	end()
+	return resp, rssi
+}
+
+func isCSMA(r Interface) bool {
+	_, ok := r.(*CSMA)
+	return ok
+}
@@ -0,0 +1,123 @@
+package radio_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+type traceKey struct{}
+
+// fakeSpan records what is done to it.
+type fakeSpan struct {
+	name   string
+	parent interface{}
+	attrs  []radio.Attribute
+	errs   []error
+	ended  bool
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...radio.Attribute) { s.attrs = append(s.attrs, attrs...) }
+func (s *fakeSpan) RecordError(err error)                  { s.errs = append(s.errs, err) }
+func (s *fakeSpan) End()                                   { s.ended = true }
+
+// fakeTracer records the spans it starts.
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string) (context.Context, radio.Span) {
+	s := &fakeSpan{name: name, parent: ctx.Value(traceKey{})}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func TestTraced(t *testing.T) {
+	const freq = 916500000
+	errSend := errors.New("send failed")
+	attr := func(key string, value interface{}) radio.Attribute { return radio.Attribute{Key: key, Value: value} }
+	cases := []struct {
+		name  string
+		radio func(r *scriptedRadio) radio.Interface
+		op    func(r radio.Interface)
+		want  fakeSpan
+	}{
+		{"init", nil, func(r radio.Interface) { r.Init(freq) },
+			fakeSpan{name: "radio.Init", attrs: []radio.Attribute{attr(radio.FrequencyAttribute, uint32(freq))}}},
+		{"send", nil, func(r radio.Interface) { r.Send([]byte{1, 2}) },
+			fakeSpan{name: "radio.Send", attrs: []radio.Attribute{
+				attr(radio.PayloadLengthAttribute, 2), attr(radio.FrequencyAttribute, uint32(freq)),
+			}}},
+		{"send error", func(r *scriptedRadio) radio.Interface { return &recorder{Radio: r.Radio, fail: errSend} },
+			func(r radio.Interface) { r.Send([]byte{1}) },
+			fakeSpan{name: "radio.Send", attrs: []radio.Attribute{
+				attr(radio.PayloadLengthAttribute, 1), attr(radio.FrequencyAttribute, uint32(freq)),
+			}, errs: []error{errSend}}},
+		{"receive", nil, func(r radio.Interface) { r.Receive(time.Millisecond) },
+			fakeSpan{name: "radio.Receive", attrs: []radio.Attribute{
+				attr(radio.FrequencyAttribute, uint32(freq)),
+				attr(radio.PayloadLengthAttribute, 3), attr(radio.RSSIAttribute, -60),
+			}}},
+		{"receive timeout", func(r *scriptedRadio) radio.Interface { r.packets = nil; return r },
+			func(r radio.Interface) { r.Receive(time.Millisecond) },
+			fakeSpan{name: "radio.Receive", attrs: []radio.Attribute{attr(radio.FrequencyAttribute, uint32(freq))}}},
+		{"exchange", nil, func(r radio.Interface) { r.SendAndReceive([]byte{1}, time.Millisecond) },
+			fakeSpan{name: "radio.SendAndReceive", attrs: []radio.Attribute{
+				attr(radio.PayloadLengthAttribute, 1), attr(radio.FrequencyAttribute, uint32(freq)),
+				attr(radio.ResponseLengthAttribute, 3), attr(radio.RSSIAttribute, -60),
+			}}},
+		{"CSMA retries", func(r *scriptedRadio) radio.Interface {
+			r.packets = nil
+			return radio.NewCSMA(r, radio.CSMAConfig{AckTimeout: time.Millisecond, MaxRetries: 2, Clock: &sleepRecorder{}})
+		}, func(r radio.Interface) { r.Send([]byte{1}) },
+			fakeSpan{name: "radio.Send", attrs: []radio.Attribute{
+				attr(radio.PayloadLengthAttribute, 1), attr(radio.FrequencyAttribute, uint32(freq)),
+				attr(radio.RetriesAttribute, 2),
+			}, errs: []error{radio.ErrNoAck}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			inner := &scriptedRadio{Radio: a, packets: []scriptedPacket{{[]byte{1, 2, 3}, -60}}}
+			var r radio.Interface = inner
+			if c.radio != nil {
+				r = c.radio(inner)
+			}
+			tracer := &fakeTracer{}
+			tr := radio.NewTraced(r, tracer)
+			tr.SetContext(context.WithValue(context.Background(), traceKey{}, "request"))
+			c.op(tr)
+			if len(tracer.spans) != 1 {
+				t.Fatalf("%d spans, want 1", len(tracer.spans))
+			}
+			want := c.want
+			want.parent = "request"
+			want.ended = true
+			if got := *tracer.spans[0]; !reflect.DeepEqual(got, want) {
+				t.Errorf("span = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestTracedContext(t *testing.T) {
+	_, a, _ := simPair(t)
+	tracer := &fakeTracer{}
+	tr := radio.NewTraced(a, tracer)
+	tr.Send([]byte{1})
+	tr.SetContext(context.WithValue(context.Background(), traceKey{}, "request"))
+	tr.Send([]byte{2})
+	tr.SetContext(nil)
+	tr.Send([]byte{3})
+	var parents []interface{}
+	for _, s := range tracer.spans {
+		parents = append(parents, s.parent)
+	}
+	if want := []interface{}{nil, "request", nil}; !reflect.DeepEqual(parents, want) {
+		t.Errorf("span parents = %v, want %v", parents, want)
+	}
+}
@@ -0,0 +1,68 @@
+package radio
+
+import (
+	"errors"
+	"time"
+)
+
+// TransmitStatusFlavor is implemented by flavors whose chips report
+// transmit completion and FIFO underflow in a status register.
+// A transmission is complete when any of the done bits are set,
+// and has failed when any of the underflow bits are set.
+type TransmitStatusFlavor interface {
+	TransmitStatus() (addr byte, done byte, underflow byte)
+}
+
+// ErrTransmitUnderflow indicates that the transmit FIFO ran empty
+// before the end of a packet.
+var ErrTransmitUnderflow = errors.New("transmit FIFO underflow")
+
+// AwaitTransmit waits for the current transmission to complete,
+// bounded by the transmit timeout, and then switches the RF path
+// back to receive.
+// If the flavor implements TransmitStatusFlavor, its status register
+// is polled; otherwise the transmit-done indication is expected
+// on the interrupt pin.
+func (h *Hardware) AwaitTransmit() {
+	// Never leave the transmit path on, even after an error.
+	defer h.restoreAntenna()
+	if h.Error() != nil {
+		return
+	}
+	if h.profiling {
+		start := time.Now()
+		defer func() { h.stats.Transmits.Add(time.Since(start)) }()
+	}
+	f, ok := h.flavor.(TransmitStatusFlavor)
+	if !ok {
+		h.err = h.waitInterrupt(h.timeouts.Transmit)
+		return
+	}
+	addr, done, underflow := f.TransmitStatus()
+	h.poll("transmit", h.timeouts.Transmit, func() bool {
+		status := h.ReadRegister(addr)
+		if status&underflow != 0 {
+			h.err = ErrTransmitUnderflow
+			h.stats.Underflows++
+			return true
+		}
+		return status&done != 0
+	})
+}
+
+// ConfirmedSender is implemented by radios that can confirm
+// that a packet has been completely transmitted.
+type ConfirmedSender interface {
+	SendConfirmed([]byte) error
+}
+
+// SendConfirmed transmits data and waits for the radio to confirm
+// that it has been sent, if the radio supports that.
+// Otherwise it returns the radio's error state after Send.
+func SendConfirmed(r Interface, data []byte) error {
+	if s, ok := r.(ConfirmedSender); ok {
+		return s.SendConfirmed(data)
+	}
+	r.Send(data)
+	return r.Error()
+}
@@ -0,0 +1,84 @@
+package radio
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	txStatusAddr = 0x28
+	txDoneBit    = 0x08
+	txUnderBit   = 0x10
+)
+
+// txFlavor reports transmit completion in a status register.
+type txFlavor struct{ testFlavor }
+
+func (txFlavor) InterruptPin() int { return -1 }
+
+func (txFlavor) TransmitStatus() (byte, byte, byte) {
+	return txStatusAddr, txDoneBit, txUnderBit
+}
+
+// interruptFlavor signals transmit completion only on the interrupt line.
+type interruptFlavor struct{ testFlavor }
+
+func (interruptFlavor) InterruptPin() int { return -1 }
+
+// confirmRadio confirms each transmission with AwaitTransmit.
+type confirmRadio struct{ *hwRadio }
+
+func (r confirmRadio) SendConfirmed(data []byte) error {
+	r.hw.WriteBurst(0x00, data)
+	r.hw.AwaitTransmit()
+	return r.hw.Error()
+}
+
+func TestSendConfirmed(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+	cases := []struct {
+		name   string
+		flavor HardwareFlavor
+		radio  func(*hwRadio) Interface
+		status byte
+		waits  bool
+		want   error
+	}{
+		{"done", txFlavor{}, func(r *hwRadio) Interface { return confirmRadio{r} }, txDoneBit, false, nil},
+		{"underflow", txFlavor{}, func(r *hwRadio) Interface { return confirmRadio{r} }, txUnderBit | txDoneBit, false, ErrTransmitUnderflow},
+		{"timeout", txFlavor{}, func(r *hwRadio) Interface { return confirmRadio{r} }, 0, true, OperationTimeoutError{Operation: "transmit", Timeout: timeout}},
+		{"interrupt timeout", interruptFlavor{}, func(r *hwRadio) Interface { return confirmRadio{r} }, 0, true, InterruptTimeoutError{Pin: -1, Timeout: timeout}},
+		{"unconfirmed", txFlavor{}, func(r *hwRadio) Interface { return r }, 0, false, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Open(c.flavor, func(h *Hardware) { h.device = &statusDevice{status: c.status} })
+			defer h.Close()
+			clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			h.SetClock(clock)
+			h.SetTimeouts(Timeouts{Transmit: timeout})
+			h.SetPollInterval(0)
+			done := make(chan error, 1)
+			go func() { done <- SendConfirmed(c.radio(&hwRadio{hw: h}), []byte{1, 2, 3}) }()
+			if c.waits {
+				for clock.Waiters() == 0 {
+					time.Sleep(time.Millisecond)
+				}
+				select {
+				case err := <-done:
+					t.Fatalf("SendConfirmed returned %v before the timeout", err)
+				default:
+				}
+				clock.Advance(timeout)
+			}
+			select {
+			case err := <-done:
+				if err != c.want {
+					t.Errorf("error = %v, want %v", err, c.want)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("SendConfirmed did not return")
+			}
+		})
+	}
+}
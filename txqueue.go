@@ -0,0 +1,212 @@
+package radio
+
+import (
+	"container/heap"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Frame is a packet waiting in a TransmitQueue.
+type Frame struct {
+	Data []byte
+	// Priority orders frames; higher priorities are sent first.
+	Priority int
+	// Deadline, if not zero, is the time after which the frame
+	// is discarded instead of sent.
+	Deadline time.Time
+	// Done, if not nil, receives the result of sending the frame.
+	// The queue's lock is not held while sending on it, but an
+	// unbuffered channel holds up the queue until it is read,
+	// so it should be buffered.
+	Done chan<- error
+}
+
+var (
+	// ErrFrameExpired is reported for frames discarded after their deadline.
+	ErrFrameExpired = errors.New("frame deadline expired")
+	// ErrQueueStopped is reported for frames still queued when the queue stops.
+	ErrQueueStopped = errors.New("transmit queue stopped")
+)
+
+type frameHeap []*queuedFrame
+
+type queuedFrame struct {
+	Frame
+	seq uint64
+}
+
+func (q frameHeap) Len() int { return len(q) }
+
+func (q frameHeap) Less(i, j int) bool {
+	if q[i].Priority != q[j].Priority {
+		return q[i].Priority > q[j].Priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q frameHeap) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *frameHeap) Push(x interface{}) { *q = append(*q, x.(*queuedFrame)) }
+
+func (q *frameHeap) Pop() interface{} {
+	old := *q
+	n := len(old)
+	f := old[n-1]
+	*q = old[:n-1]
+	return f
+}
+
+// TransmitQueue serializes transmissions from multiple goroutines
+// through a single radio, sending higher-priority frames first
+// and frames of equal priority in the order they were queued.
+type TransmitQueue struct {
+	radio Interface
+	wg    sync.WaitGroup
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	frames  frameHeap
+	seq     uint64
+	sent    int
+	failed  int
+	expired int
+	stopped bool
+	clocked
+}
+
+// NewTransmitQueue starts a TransmitQueue that sends frames with r.
+func NewTransmitQueue(r Interface) *TransmitQueue {
+	q := &TransmitQueue{radio: r}
+	q.cond = sync.NewCond(&q.mu)
+	q.wg.Add(1)
+	go q.loop()
+	return q
+}
+
+// SetClock sets the Clock against which frame deadlines are checked.
+// A nil Clock means SystemClock.
+func (q *TransmitQueue) SetClock(clock Clock) {
+	q.mu.Lock()
+	q.clock = clock
+	q.mu.Unlock()
+}
+
+// Enqueue adds a frame to the queue.
+func (q *TransmitQueue) Enqueue(f Frame) {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		complete(f, ErrQueueStopped)
+		return
+	}
+	q.seq++
+	heap.Push(&q.frames, &queuedFrame{Frame: f, seq: q.seq})
+	q.cond.Signal()
+	q.mu.Unlock()
+}
+
+// Len returns the number of frames waiting to be sent.
+func (q *TransmitQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.frames)
+}
+
+// Sent returns the number of frames that have been sent successfully.
+func (q *TransmitQueue) Sent() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.sent
+}
+
+// Failed returns the number of frames whose transmission failed.
+func (q *TransmitQueue) Failed() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.failed
+}
+
+// Expired returns the number of frames discarded after their deadline.
+func (q *TransmitQueue) Expired() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.expired
+}
+
+// Stop waits for any frame being sent, then discards the remaining frames.
+func (q *TransmitQueue) Stop() {
+	q.mu.Lock()
+	q.stopped = true
+	q.cond.Signal()
+	q.mu.Unlock()
+	q.wg.Wait()
+	q.mu.Lock()
+	frames := q.frames
+	q.frames = nil
+	q.mu.Unlock()
+	for len(frames) != 0 {
+		f := heap.Pop(&frames).(*queuedFrame)
+		complete(f.Frame, ErrQueueStopped)
+	}
+}
+
+func (q *TransmitQueue) loop() {
+	defer q.wg.Done()
+	for {
+		f, expired, stopped := q.next()
+		for _, e := range expired {
+			complete(e.Frame, ErrFrameExpired)
+		}
+		if stopped {
+			return
+		}
+		if f == nil {
+			continue
+		}
+		q.radio.Send(f.Data)
+		err := q.radio.Error()
+		q.radio.SetError(nil)
+		q.mu.Lock()
+		if err == nil {
+			q.sent++
+		} else {
+			q.failed++
+		}
+		q.mu.Unlock()
+		complete(f.Frame, err)
+	}
+}
+
+// next waits for the next unexpired frame or for the queue to stop.
+// It also returns the frames that expired while it was looking,
+// to be completed once the lock is released; if the queue empties,
+// it returns them with no frame rather than waiting.
+func (q *TransmitQueue) next() (f *queuedFrame, expired []*queuedFrame, stopped bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for {
+		for len(q.frames) == 0 && !q.stopped {
+			q.cond.Wait()
+		}
+		if q.stopped {
+			return nil, expired, true
+		}
+		f = heap.Pop(&q.frames).(*queuedFrame)
+		if !f.Deadline.IsZero() && q.now().After(f.Deadline) {
+			q.expired++
+			expired = append(expired, f)
+			if len(q.frames) == 0 {
+				return nil, expired, false
+			}
+			continue
+		}
+		return f, expired, false
+	}
+}
+
+func complete(f Frame, err error) {
+	if f.Done != nil {
+		f.Done <- err
+	}
+}
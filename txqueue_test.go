@@ -0,0 +1,124 @@
+package radio_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+func TestTransmitQueuePriority(t *testing.T) {
+	_, a, _ := simPair(t)
+	r := &recorder{Radio: a, gate: make(chan struct{})}
+	q := radio.NewTransmitQueue(r)
+	defer q.Stop()
+	done := make(chan error, 4)
+	// The first frame is taken immediately and blocks in Send;
+	// the rest are queued behind it and sent in priority order.
+	q.Enqueue(radio.Frame{Data: []byte{0}, Done: done})
+	time.Sleep(10 * time.Millisecond)
+	q.Enqueue(radio.Frame{Data: []byte{1}, Priority: 0, Done: done})
+	q.Enqueue(radio.Frame{Data: []byte{2}, Priority: 5, Done: done})
+	q.Enqueue(radio.Frame{Data: []byte{3}, Priority: 0, Done: done})
+	close(r.gate)
+	for i := 0; i < 4; i++ {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []byte{0, 2, 1, 3}
+	got := r.packets()
+	for i, p := range got {
+		if p[0] != want[i] {
+			t.Fatalf("send order %v, want %v", got, want)
+		}
+	}
+	if q.Sent() != 4 || q.Failed() != 0 {
+		t.Errorf("sent %d failed %d, want 4 and 0", q.Sent(), q.Failed())
+	}
+}
+
+func TestTransmitQueueResults(t *testing.T) {
+	errSend := errors.New("send failed")
+	cases := []struct {
+		name    string
+		frame   radio.Frame
+		fail    error
+		err     error
+		sent    int
+		failed  int
+		expired int
+	}{
+		{"Sent", radio.Frame{Data: []byte{1}}, nil, nil, 1, 0, 0},
+		{"Failed", radio.Frame{Data: []byte{1}}, errSend, errSend, 0, 1, 0},
+		{"Expired", radio.Frame{Data: []byte{1}, Deadline: time.Now().Add(-time.Second)}, nil, radio.ErrFrameExpired, 0, 0, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			q := radio.NewTransmitQueue(&recorder{Radio: a, fail: c.fail})
+			done := make(chan error, 1)
+			c.frame.Done = done
+			q.Enqueue(c.frame)
+			if err := <-done; err != c.err {
+				t.Errorf("result %v, want %v", err, c.err)
+			}
+			q.Stop()
+			if q.Sent() != c.sent || q.Failed() != c.failed || q.Expired() != c.expired {
+				t.Errorf("sent %d failed %d expired %d, want %d %d %d",
+					q.Sent(), q.Failed(), q.Expired(), c.sent, c.failed, c.expired)
+			}
+		})
+	}
+}
+
+// TestTransmitQueueUnbufferedDone checks that a Done channel that is
+// not read promptly does not block other users of the queue.
+func TestTransmitQueueUnbufferedDone(t *testing.T) {
+	_, a, _ := simPair(t)
+	q := radio.NewTransmitQueue(&recorder{Radio: a})
+	done := make(chan error)
+	q.Enqueue(radio.Frame{Data: []byte{1}, Done: done})
+	ok := make(chan struct{})
+	go func() {
+		q.Enqueue(radio.Frame{Data: []byte{2}})
+		_ = q.Len()
+		_ = q.Sent()
+		close(ok)
+	}()
+	select {
+	case <-ok:
+	case <-time.After(time.Second):
+		t.Fatal("queue blocked on an unread Done channel")
+	}
+	<-done
+	q.Stop()
+}
+
+func TestTransmitQueueStop(t *testing.T) {
+	_, a, _ := simPair(t)
+	r := &recorder{Radio: a, gate: make(chan struct{})}
+	q := radio.NewTransmitQueue(r)
+	first := make(chan error, 1)
+	q.Enqueue(radio.Frame{Data: []byte{0}, Done: first})
+	time.Sleep(10 * time.Millisecond)
+	queued := make(chan error, 1)
+	q.Enqueue(radio.Frame{Data: []byte{1}, Done: queued})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(r.gate)
+	}()
+	q.Stop()
+	if err := <-first; err != nil {
+		t.Errorf("frame in progress: %v", err)
+	}
+	if err := <-queued; err != radio.ErrQueueStopped {
+		t.Errorf("queued frame: %v, want %v", err, radio.ErrQueueStopped)
+	}
+	late := make(chan error, 1)
+	q.Enqueue(radio.Frame{Data: []byte{2}, Done: late})
+	if err := <-late; err != radio.ErrQueueStopped {
+		t.Errorf("frame after Stop: %v, want %v", err, radio.ErrQueueStopped)
+	}
+}
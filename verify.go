@@ -0,0 +1,50 @@
+package radio
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// WriteOnlyFlavor is implemented by flavors with registers that cannot
+// be read back as written, such as FIFOs, strobes, and status registers.
+type WriteOnlyFlavor interface {
+	WriteOnly(addr byte) bool
+}
+
+// WriteVerifyError indicates that a register did not hold the value written to it.
+type WriteVerifyError struct {
+	Addr  byte
+	Wrote []byte
+	Read  []byte
+}
+
+func (e WriteVerifyError) Error() string {
+	return fmt.Sprintf("register %02X: wrote % X but read back % X", e.Addr, e.Wrote, e.Read)
+}
+
+// SetVerifyWrites controls whether each register write is read back and
+// compared with the value written, which catches signal integrity
+// problems on the SPI bus. Addresses that the flavor declares
+// write-only (see WriteOnlyFlavor) are not verified.
+// A mismatch sets the error state to a WriteVerifyError.
+func (h *Hardware) SetVerifyWrites(verify bool) {
+	h.verifyWrites = verify
+}
+
+func (h *Hardware) verify(addr byte, data []byte) {
+	if !h.verifyWrites || h.Error() != nil {
+		return
+	}
+	if f, ok := h.flavor.(WriteOnlyFlavor); ok && f.WriteOnly(addr) {
+		return
+	}
+	var got []byte
+	if len(data) == 1 {
+		got = []byte{h.ReadRegister(addr)}
+	} else {
+		got = h.ReadBurst(addr, len(data))
+	}
+	if h.Error() == nil && !bytes.Equal(got, data) {
+		h.err = WriteVerifyError{Addr: addr, Wrote: append([]byte(nil), data...), Read: got}
+	}
+}
@@ -0,0 +1,45 @@
+package radio
+
+import (
+	"reflect"
+	"testing"
+)
+
+// verifyFlavor declares address 0x00 write-only.
+type verifyFlavor struct{ testFlavor }
+
+func (verifyFlavor) InterruptPin() int        { return -1 }
+func (verifyFlavor) WriteOnly(addr byte) bool { return addr == 0x00 }
+
+func TestVerifyWrites(t *testing.T) {
+	const status = 0x5A
+	cases := []struct {
+		name   string
+		verify bool
+		addr   byte
+		data   []byte
+		want   error
+	}{
+		{"register match", true, 0x10, []byte{status}, nil},
+		{"register mismatch", true, 0x10, []byte{0x01}, WriteVerifyError{Addr: 0x10, Wrote: []byte{0x01}, Read: []byte{status}}},
+		{"burst match", true, 0x10, []byte{status, status, status}, nil},
+		{"burst mismatch", true, 0x10, []byte{status, 0x02, status}, WriteVerifyError{Addr: 0x10, Wrote: []byte{status, 0x02, status}, Read: []byte{status, status, status}}},
+		{"write-only", true, 0x00, []byte{0x01}, nil},
+		{"disabled", false, 0x10, []byte{0x01}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := Open(verifyFlavor{}, func(h *Hardware) { h.device = &statusDevice{status: status} })
+			defer h.Close()
+			h.SetVerifyWrites(c.verify)
+			if len(c.data) == 1 {
+				h.WriteRegister(c.addr, c.data[0])
+			} else {
+				h.WriteBurst(c.addr, c.data)
+			}
+			if err := h.Error(); !reflect.DeepEqual(err, c.want) {
+				t.Errorf("error = %v, want %v", err, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package radio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// A waterfall records RSSI over time on one or more frequencies.
+// It begins with a big-endian header listing the frequencies:
+//
+//	magic      [4]byte  "RWF1"
+//	count      uint16   number of frequencies
+//	frequency  uint32   Hertz, repeated count times
+//
+// followed by a sequence of fixed-size rows:
+//
+//	timestamp  int64    nanoseconds since the Unix epoch
+//	rssi       int8     dBm, repeated count times
+var waterfallMagic = [4]byte{'R', 'W', 'F', '1'}
+
+// ErrNotWaterfall indicates that data does not begin with a waterfall header.
+var ErrNotWaterfall = errors.New("not an RSSI waterfall")
+
+// ErrNoRSSI indicates that a radio cannot report RSSI.
+var ErrNoRSSI = errors.New("radio does not support reading RSSI")
+
+// WaterfallRow is one row of a waterfall: the RSSI on each
+// frequency during the interval starting at Time.
+type WaterfallRow struct {
+	Time time.Time
+	RSSI []int
+}
+
+// WaterfallWriter writes rows to a waterfall.
+type WaterfallWriter struct {
+	w     io.Writer
+	count int
+	buf   []byte
+}
+
+// NewWaterfallWriter writes a waterfall header for the given
+// frequencies to w and returns a WaterfallWriter for its rows.
+func NewWaterfallWriter(w io.Writer, freqs []uint32) (*WaterfallWriter, error) {
+	n := len(freqs)
+	if n == 0 || n > 1<<16-1 {
+		return nil, fmt.Errorf("invalid number of waterfall frequencies (%d)", n)
+	}
+	hdr := make([]byte, 6+4*n)
+	copy(hdr, waterfallMagic[:])
+	binary.BigEndian.PutUint16(hdr[4:], uint16(n))
+	for i, f := range freqs {
+		binary.BigEndian.PutUint32(hdr[6+4*i:], f)
+	}
+	_, err := w.Write(hdr)
+	if err != nil {
+		return nil, err
+	}
+	return &WaterfallWriter{w: w, count: n, buf: make([]byte, 8+n)}, nil
+}
+
+// Write appends row to the waterfall.
+// RSSI values are clamped to the range of an int8.
+func (t *WaterfallWriter) Write(row WaterfallRow) error {
+	if len(row.RSSI) != t.count {
+		return fmt.Errorf("waterfall row has %d values (should be %d)", len(row.RSSI), t.count)
+	}
+	binary.BigEndian.PutUint64(t.buf[0:], uint64(row.Time.UnixNano()))
+	for i, rssi := range row.RSSI {
+		if rssi < -128 {
+			rssi = -128
+		} else if rssi > 127 {
+			rssi = 127
+		}
+		t.buf[8+i] = byte(int8(rssi))
+	}
+	_, err := t.w.Write(t.buf)
+	return err
+}
+
+// WaterfallReader reads rows from a waterfall.
+type WaterfallReader struct {
+	r     io.Reader
+	freqs []uint32
+	buf   []byte
+}
+
+// NewWaterfallReader reads a waterfall header from r
+// and returns a WaterfallReader for its rows.
+func NewWaterfallReader(r io.Reader) (*WaterfallReader, error) {
+	var hdr [6]byte
+	_, err := io.ReadFull(r, hdr[:])
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(hdr[:4], waterfallMagic[:]) {
+		return nil, ErrNotWaterfall
+	}
+	n := int(binary.BigEndian.Uint16(hdr[4:]))
+	buf := make([]byte, 4*n)
+	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		return nil, err
+	}
+	freqs := make([]uint32, n)
+	for i := range freqs {
+		freqs[i] = binary.BigEndian.Uint32(buf[4*i:])
+	}
+	return &WaterfallReader{r: r, freqs: freqs, buf: make([]byte, 8+n)}, nil
+}
+
+// Frequencies returns the frequencies recorded in the waterfall.
+func (t *WaterfallReader) Frequencies() []uint32 {
+	return t.freqs
+}
+
+// Read returns the next row in the waterfall,
+// or io.EOF when there are no more rows.
+func (t *WaterfallReader) Read() (WaterfallRow, error) {
+	_, err := io.ReadFull(t.r, t.buf)
+	if err != nil {
+		return WaterfallRow{}, err
+	}
+	row := WaterfallRow{
+		Time: time.Unix(0, int64(binary.BigEndian.Uint64(t.buf[0:]))),
+		RSSI: make([]int, len(t.freqs)),
+	}
+	for i := range row.RSSI {
+		row.RSSI[i] = int(int8(t.buf[8+i]))
+	}
+	return row, nil
+}
+
+// WaterfallConfig configures a WaterfallRecorder.
+type WaterfallConfig struct {
+	// Frequencies are scanned in order during each sweep.
+	// If empty, the radio's current frequency is sampled without retuning.
+	Frequencies []uint32
+	// Dwell is the time spent sampling each frequency per sweep.
+	Dwell time.Duration
+	// SampleInterval is the time between RSSI samples while dwelling.
+	SampleInterval time.Duration
+	// Interval is the time covered by each recorded row.
+	// Sweeps are repeated within it and each frequency's
+	// strongest sample is recorded, so brief bursts of
+	// interference are not averaged away.
+	Interval time.Duration
+	// Clock provides the time. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// DefaultWaterfallConfig records one row per second, sampling each
+// frequency every millisecond for 10ms per sweep.
+var DefaultWaterfallConfig = WaterfallConfig{
+	Dwell:          10 * time.Millisecond,
+	SampleInterval: time.Millisecond,
+	Interval:       time.Second,
+}
+
+// WaterfallRecorder samples RSSI in the background and writes a
+// downsampled waterfall. The radio should be dedicated to it and
+// left in receive mode; the original frequency is restored when the
+// recorder stops. Sampling requires an RSSIReader in the wrapped chain.
+type WaterfallRecorder struct {
+	radio  Interface
+	rssi   RSSIReader
+	w      *WaterfallWriter
+	config WaterfallConfig
+	freqs  []uint32
+	clocked
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+	err  error
+}
+
+// NewWaterfallRecorder starts recording a waterfall for r to w,
+// which must have been created with the same frequencies as config.
+func NewWaterfallRecorder(r Interface, w *WaterfallWriter, config WaterfallConfig) (*WaterfallRecorder, error) {
+	rssi, ok := Find(r, isRSSIReader).(RSSIReader)
+	if !ok {
+		return nil, ErrNoRSSI
+	}
+	freqs := config.Frequencies
+	if len(freqs) == 0 {
+		freqs = []uint32{r.Frequency()}
+	}
+	if len(freqs) != w.count {
+		return nil, fmt.Errorf("waterfall writer has %d frequencies (should be %d)", w.count, len(freqs))
+	}
+	if config.SampleInterval <= 0 {
+		config.SampleInterval = DefaultWaterfallConfig.SampleInterval
+	}
+	if config.Dwell < config.SampleInterval {
+		config.Dwell = config.SampleInterval
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultWaterfallConfig.Interval
+	}
+	rec := &WaterfallRecorder{
+		radio:   r,
+		rssi:    rssi,
+		w:       w,
+		config:  config,
+		freqs:   freqs,
+		clocked: clocked{config.Clock},
+		stop:    make(chan struct{}),
+	}
+	rec.wg.Add(1)
+	go rec.loop()
+	return rec, nil
+}
+
+// Stop stops the recorder and returns the first error it encountered.
+// It may be called more than once.
+func (rec *WaterfallRecorder) Stop() error {
+	rec.once.Do(func() { close(rec.stop) })
+	rec.wg.Wait()
+	return rec.err
+}
+
+func (rec *WaterfallRecorder) loop() {
+	defer rec.wg.Done()
+	retune := len(rec.config.Frequencies) != 0
+	original := rec.radio.Frequency()
+	if retune {
+		defer rec.radio.SetFrequency(original)
+	}
+	for {
+		start := rec.now()
+		row := WaterfallRow{Time: start, RSSI: make([]int, len(rec.freqs))}
+		sampled := make([]bool, len(rec.freqs))
+		for rec.now().Sub(start) < rec.config.Interval {
+			for i, f := range rec.freqs {
+				if retune {
+					rec.radio.SetFrequency(f)
+				}
+				if !rec.dwell(&row.RSSI[i], &sampled[i]) {
+					return
+				}
+			}
+		}
+		if err := rec.radio.Error(); err != nil {
+			rec.err = err
+			return
+		}
+		if err := rec.w.Write(row); err != nil {
+			rec.err = err
+			return
+		}
+	}
+}
+
+// dwell samples the current frequency, keeping the maximum RSSI in *max.
+// It returns false if the recorder has been stopped.
+func (rec *WaterfallRecorder) dwell(max *int, sampled *bool) bool {
+	deadline := rec.now().Add(rec.config.Dwell)
+	for rec.now().Before(deadline) {
+		rssi := rec.rssi.ReadRSSI()
+		if !*sampled || rssi > *max {
+			*max = rssi
+			*sampled = true
+		}
+		select {
+		case <-rec.stop:
+			return false
+		case <-rec.after(rec.config.SampleInterval):
+		}
+	}
+	return true
+}
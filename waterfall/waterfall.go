@@ -0,0 +1,153 @@
+// Package waterfall exports RSSI waterfalls recorded by
+// radio.WaterfallRecorder to CSV and PNG, for viewing in a
+// spreadsheet or image viewer when diagnosing interference.
+//
+// It is a separate package so that programs which only record
+// waterfalls do not link the image encoders.
+package waterfall
+
+import (
+	"encoding/csv"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+// readAll returns the remaining rows of a waterfall.
+func readAll(r *radio.WaterfallReader) ([]radio.WaterfallRow, error) {
+	var rows []radio.WaterfallRow
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+// WriteCSV writes the rows of a waterfall as CSV, with a header
+// line giving the frequency of each column after the timestamp.
+func WriteCSV(w io.Writer, r *radio.WaterfallReader) error {
+	c := csv.NewWriter(w)
+	freqs := r.Frequencies()
+	rec := make([]string, 1+len(freqs))
+	rec[0] = "time"
+	for i, f := range freqs {
+		rec[1+i] = strconv.FormatUint(uint64(f), 10)
+	}
+	err := c.Write(rec)
+	for err == nil {
+		var row radio.WaterfallRow
+		row, err = r.Read()
+		if err != nil {
+			break
+		}
+		rec[0] = row.Time.Format(time.RFC3339Nano)
+		for i, rssi := range row.RSSI {
+			rec[1+i] = strconv.Itoa(rssi)
+		}
+		err = c.Write(rec)
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	c.Flush()
+	if err == nil {
+		err = c.Error()
+	}
+	return err
+}
+
+// PNGOptions configures WritePNG.
+type PNGOptions struct {
+	// Min and Max are the RSSI values, in dBm, mapped to the ends of
+	// the color scale. If both are zero, the range of the data is used.
+	Min int
+	Max int
+	// CellWidth is the width in pixels of each frequency column.
+	// Zero means 1.
+	CellWidth int
+}
+
+// WritePNG renders a waterfall as a PNG image with one column per
+// frequency and one line per row, earliest at the top. Weak signals
+// are drawn in dark blue and strong ones in red through yellow.
+func WritePNG(w io.Writer, r *radio.WaterfallReader, opts PNGOptions) error {
+	rows, err := readAll(r)
+	if err != nil {
+		return err
+	}
+	min, max := opts.Min, opts.Max
+	if min == 0 && max == 0 {
+		min, max = bounds(rows)
+	}
+	cell := opts.CellWidth
+	if cell <= 0 {
+		cell = 1
+	}
+	n := len(r.Frequencies())
+	img := image.NewRGBA(image.Rect(0, 0, n*cell, len(rows)))
+	for y, row := range rows {
+		for i, rssi := range row.RSSI {
+			c := heat(rssi, min, max)
+			for x := i * cell; x < (i+1)*cell; x++ {
+				img.Set(x, y, c)
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+func bounds(rows []radio.WaterfallRow) (min int, max int) {
+	first := true
+	for _, row := range rows {
+		for _, rssi := range row.RSSI {
+			if first || rssi < min {
+				min = rssi
+			}
+			if first || rssi > max {
+				max = rssi
+			}
+			first = false
+		}
+	}
+	return min, max
+}
+
+// heat maps rssi in [min, max] to a color from dark blue through
+// cyan, green, and yellow to red.
+func heat(rssi int, min int, max int) color.Color {
+	t := 0.0
+	if max > min {
+		t = float64(rssi-min) / float64(max-min)
+	}
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	stops := []color.RGBA{
+		{0, 0, 64, 255},
+		{0, 128, 255, 255},
+		{0, 192, 0, 255},
+		{255, 255, 0, 255},
+		{255, 0, 0, 255},
+	}
+	t *= float64(len(stops) - 1)
+	i := int(t)
+	if i == len(stops)-1 {
+		return stops[i]
+	}
+	f := t - float64(i)
+	a, b := stops[i], stops[i+1]
+	mix := func(x, y uint8) uint8 { return uint8(float64(x) + f*(float64(y)-float64(x))) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
@@ -0,0 +1,90 @@
+package radio_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/ecc1/radio"
+)
+
+func TestWaterfallRecorder(t *testing.T) {
+	const freq = 916500000
+	cases := []struct {
+		name  string
+		freqs []uint32
+	}{
+		{"current frequency", nil},
+		{"sweep", []uint32{freq - 100000, freq + 100000}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, a, _ := simPair(t)
+			r := busyRadio{a, -70}
+			header := c.freqs
+			if header == nil {
+				header = []uint32{freq}
+			}
+			var buf bytes.Buffer
+			w, err := radio.NewWaterfallWriter(&buf, header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config := radio.WaterfallConfig{
+				Frequencies:    c.freqs,
+				Dwell:          time.Millisecond,
+				SampleInterval: time.Millisecond,
+				Interval:       5 * time.Millisecond,
+			}
+			rec, err := radio.NewWaterfallRecorder(r, w, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(30 * time.Millisecond)
+			for i := 0; i < 2; i++ {
+				if err := rec.Stop(); err != nil {
+					t.Errorf("Stop %d: %v", i+1, err)
+				}
+			}
+			if f := a.Frequency(); f != freq {
+				t.Errorf("frequency %d after Stop, want %d", f, freq)
+			}
+			rd, err := radio.NewWaterfallReader(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows := 0
+			for {
+				row, err := rd.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				rows++
+				for i, rssi := range row.RSSI {
+					if rssi != r.rssi {
+						t.Errorf("row %d: RSSI[%d] = %d, want %d", rows, i, rssi, r.rssi)
+					}
+				}
+			}
+			if rows == 0 {
+				t.Error("no rows recorded")
+			}
+		})
+	}
+}
+
+func TestWaterfallRecorderNoRSSI(t *testing.T) {
+	_, a, _ := simPair(t)
+	w, err := radio.NewWaterfallWriter(ioutil.Discard, []uint32{a.Frequency()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := radio.NewWaterfallRecorder(a, w, radio.DefaultWaterfallConfig); err != radio.ErrNoRSSI {
+		t.Errorf("error = %v, want %v", err, radio.ErrNoRSSI)
+	}
+}
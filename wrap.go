@@ -0,0 +1,70 @@
+package radio
+
+// Middleware wraps a radio to add behavior to it, in the manner of
+// http.Handler middleware. A typical wrapper embeds the Interface it
+// wraps, overrides the methods it changes, and implements Unwrapper:
+//
+//	type logger struct{ radio.Interface }
+//
+//	func (l logger) Send(data []byte) {
+//		log.Printf("send % X", data)
+//		l.Interface.Send(data)
+//	}
+//
+//	func (l logger) Unwrap() radio.Interface { return l.Interface }
+//
+//	func Logger(r radio.Interface) radio.Interface { return logger{r} }
+type Middleware func(Interface) Interface
+
+// Wrap applies the given middleware to r. The first middleware is
+// outermost, so it sees each packet sent first and each packet
+// received last.
+func Wrap(r Interface, middleware ...Middleware) Interface {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		r = middleware[i](r)
+	}
+	return r
+}
+
+// Unwrapper is implemented by wrappers that expose the radio they wrap.
+type Unwrapper interface {
+	Unwrap() Interface
+}
+
+// Unwrap returns the radio wrapped by r, or nil if r is not a wrapper.
+func Unwrap(r Interface) Interface {
+	if u, ok := r.(Unwrapper); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// Find returns the outermost radio in the chain of wrappers
+// starting at r for which match returns true, or nil if there is none.
+// It can be used to reach optional interfaces hidden by wrappers:
+//
+//	rssi, ok := radio.Find(r, func(r radio.Interface) bool {
+//		_, ok := r.(radio.RSSIReader)
+//		return ok
+//	}).(radio.RSSIReader)
+func Find(r Interface, match func(Interface) bool) Interface {
+	for r != nil {
+		if match(r) {
+			return r
+		}
+		r = Unwrap(r)
+	}
+	return nil
+}
+
+// Unwrap returns the radio wrapped by s.
+func (s *Squelch) Unwrap() Interface {
+	return s.Interface
+}
+
+// WithSquelch returns Middleware that wraps radios in a Squelch with the given margin.
+func WithSquelch(margin int) Middleware {
+	return func(r Interface) Interface {
+		return NewSquelch(r, margin)
+	}
+}